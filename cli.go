@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"gokana/internal/deck"
+	"gokana/internal/engine"
+	"gokana/internal/game"
+	"gokana/internal/model"
+	"gokana/internal/replay"
+	"gokana/internal/scores"
+	"gokana/internal/stats"
+)
+
+const usage = `Usage: gokana [command]
+
+Commands:
+  play [flags]     start a run right away, skipping the menu
+  stats            print the statistics of every kana seen
+  decks            list the custom decks
+  scores           print the high scores of every configuration
+  replay <file>    watch a recorded run
+
+Without a command the menu opens. Run "gokana play -h" for the play flags.
+`
+
+// usageError is a command line that could not be understood
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// leaderboardSize is the number of scores printed per configuration, as in the game
+const leaderboardSize = 10
+
+// modeNames are the --mode values
+var modeNames = map[string]model.GameMode{
+	"classic": model.ModeClassic,
+	"reverse": model.ModeReverse,
+	"typing":  model.ModeTyping,
+	"words":   model.ModeWords,
+	"quiz":    model.ModeQuiz,
+	"drill":   model.ModeDrill,
+}
+
+// run dispatches the command line to its command
+func run(args []string) error {
+	if len(args) == 0 {
+		return runGame(engine.New(game.InitialModel(), engine.SystemClock{}, nil), nil)
+	}
+	switch args[0] {
+	case "play":
+		return play(args[1:])
+	case "stats":
+		return noArgs("stats", args[1:], printStats)
+	case "decks":
+		return noArgs("decks", args[1:], printDecks)
+	case "scores":
+		return noArgs("scores", args[1:], printScores)
+	case "replay":
+		if len(args) != 2 {
+			return usageError("replay takes the replay file to watch")
+		}
+		return replay.Run(args[1])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		return usageError(fmt.Sprintf("unknown command %q", args[0]))
+	}
+}
+
+// parseFlags parses the flags of a command. -h prints the flags and returns
+// flag.ErrHelp, a bad flag is a usage error reported by main.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		fs.SetOutput(os.Stdout)
+		fs.Usage()
+		return err
+	}
+	if err != nil {
+		return usageError(err.Error())
+	}
+	return nil
+}
+
+// noArgs runs a command taking neither flags nor arguments
+func noArgs(name string, args []string, command func() error) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gokana %s\n", name)
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(name + " takes no arguments")
+	}
+	return command()
+}
+
+// play starts a run with the settings of the menu, overridden by the flags
+func play(args []string) error {
+	m := game.InitialModel()
+
+	fs := flag.NewFlagSet("play", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gokana play [flags]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	kana := fs.String("kana", "", "character set: hiragana, katakana, both or the name of a custom deck")
+	fs.BoolVar(&m.DakutenEnabled, "dakuten", m.DakutenEnabled, "include kana with dakuten and handakuten")
+	fs.IntVar(&m.StartLevel, "level", m.StartLevel, "starting level, 1-10")
+	fs.IntVar(&m.StartLives, "lives", m.StartLives, "starting lives, 1-10")
+	mode := fs.String("mode", "", "game mode: classic, reverse, typing, words, quiz or drill")
+	seed := fs.Int64("seed", 0, "seed of the run, the same seed and settings give the same kana (default random)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError("play takes no arguments, only flags")
+	}
+
+	if *kana != "" {
+		if err := selectKana(m, *kana); err != nil {
+			return err
+		}
+	}
+	if *mode != "" {
+		gameMode, ok := modeNames[strings.ToLower(*mode)]
+		if !ok {
+			return usageError(fmt.Sprintf("unknown mode %q", *mode))
+		}
+		m.Mode = gameMode
+	}
+	if m.StartLevel < 1 || m.StartLevel > 10 {
+		return usageError(fmt.Sprintf("level %d is not between 1 and 10", m.StartLevel))
+	}
+	if m.StartLives < 1 || m.StartLives > 10 {
+		return usageError(fmt.Sprintf("%d lives is not between 1 and 10", m.StartLives))
+	}
+	seeded := false
+	fs.Visit(func(f *flag.Flag) {
+		seeded = seeded || f.Name == "seed"
+	})
+	if !seeded {
+		*seed = game.NewSeed()
+	}
+
+	e := engine.New(m, engine.SystemClock{}, nil)
+	return runGame(e, game.StartGame(e, *seed))
+}
+
+// selectKana selects a built-in character set or a custom deck by name
+func selectKana(m *model.Model, name string) error {
+	for _, k := range []model.KanaType{model.KanaTypeHiragana, model.KanaTypeKatakana, model.KanaTypeBoth} {
+		if strings.EqualFold(name, k.String()) {
+			m.SelectedKana = k
+			m.MenuCursor = m.KanaOptionIndex()
+			return nil
+		}
+	}
+	for i, d := range m.Decks {
+		if strings.EqualFold(name, d.Name) {
+			m.SelectedKana = model.KanaTypeDeck
+			m.SelectedDeck = i
+			m.MenuCursor = m.KanaOptionIndex()
+			return nil
+		}
+	}
+	return usageError(fmt.Sprintf("unknown character set %q, run \"gokana decks\" to list the custom decks", name))
+}
+
+// printStats prints every kana seen, the least accurate first
+func printStats() error {
+	path, err := stats.Path()
+	if err != nil {
+		return err
+	}
+	store, err := stats.Load(path)
+	if err != nil {
+		return err
+	}
+	if len(store.Kana) == 0 {
+		fmt.Println("No statistics yet, play a game first.")
+		return nil
+	}
+
+	characters := make([]string, 0, len(store.Kana))
+	for character := range store.Kana {
+		characters = append(characters, character)
+	}
+	slices.SortFunc(characters, func(a, b string) int {
+		if d := store.Get(a).Accuracy() - store.Get(b).Accuracy(); d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KANA\tATTEMPTS\tCORRECT\tMISSES\tWRONG PICKS\tWRONG INPUTS\tACCURACY\tAVG TIME")
+	for _, character := range characters {
+		e := store.Get(character)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%.0f%%\t%s\n", character, e.Attempts, e.Correct, e.Misses,
+			e.WrongAnswers, e.WrongPrefix, e.Accuracy()*100, e.AverageAnswerTime())
+	}
+	return w.Flush()
+}
+
+// printDecks lists the custom decks and the files that failed to load
+func printDecks() error {
+	dir, err := deck.Dir()
+	if err != nil {
+		return err
+	}
+	decks, errs := deck.LoadAll(dir)
+	if len(decks) == 0 && len(errs) == 0 {
+		fmt.Printf("No custom decks in %s\n", dir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKANA\tDESCRIPTION\tFILE")
+	for _, d := range decks {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", d.Name, len(d.Kana), d.Description, d.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+	}
+	return nil
+}
+
+// printScores prints the best scores of every configuration played
+func printScores() error {
+	path, err := scores.Path()
+	if err != nil {
+		return err
+	}
+	board, err := scores.Load(path)
+	if err != nil {
+		return err
+	}
+	configs := board.Configs()
+	if len(configs) == 0 {
+		fmt.Println("No high scores yet, play a game first.")
+		return nil
+	}
+
+	for i, c := range configs {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(c.String())
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tPOINTS\tCORRECT\tLEVEL\tDATE")
+		for rank, s := range board.Top(c, leaderboardSize) {
+			fmt.Fprintf(w, "%d.\t%d\t%d\t%d\t%s\n", rank+1, s.Points, s.Correct, s.Level,
+				s.Date.Local().Format("2006-01-02 15:04"))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package config keeps the last menu configuration across sessions.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gokana/internal/model"
+	"gokana/internal/xdg"
+)
+
+// Config holds the menu settings, with the custom deck kept by name since
+// deck indexes change as deck files come and go
+type Config struct {
+	model.Settings
+	Deck string `json:"deck,omitempty"`
+}
+
+// Path returns the location of the config file
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "gokana", "config.json"), nil
+}
+
+// Load reads the config file at path. A missing file yields the default
+// settings, and so does any setting missing from the file or out of range.
+func Load(path string) (Config, error) {
+	c := Config{Settings: model.DefaultSettings()}
+	// Rows left out of a saved selection are unselected, not defaulted
+	c.SelectedRows = nil
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{Settings: model.DefaultSettings()}, nil
+	}
+	if err != nil {
+		return Config{Settings: model.DefaultSettings()}, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Config{Settings: model.DefaultSettings()}, fmt.Errorf("%s: %w", path, err)
+	}
+	c.sanitize()
+	return c, nil
+}
+
+// sanitize replaces the settings a hand-edited or newer file got wrong by their defaults
+func (c *Config) sanitize() {
+	d := model.DefaultSettings()
+	if c.Mode < 0 || c.Mode >= model.GameModeCount {
+		c.Mode = d.Mode
+	}
+	if c.SelectedKana < 0 || c.SelectedKana > model.KanaTypeDeck {
+		c.SelectedKana = d.SelectedKana
+	}
+	if c.Romanization < 0 || c.Romanization >= model.RomanizationSystemCount {
+		c.Romanization = d.Romanization
+	}
+	if c.SelectedRows == nil {
+		c.SelectedRows = d.SelectedRows
+	}
+	if c.SpawnMode != model.SpawnUniform && c.SpawnMode != model.SpawnAdaptive {
+		c.SpawnMode = d.SpawnMode
+	}
+	if c.StartLevel < 1 || c.StartLevel > 10 {
+		c.StartLevel = d.StartLevel
+	}
+	if c.StartLives < 1 || c.StartLives > 10 {
+		c.StartLives = d.StartLives
+	}
+	if c.TimeAttack < 0 || c.TimeAttack >= model.TimeAttackCount {
+		c.TimeAttack = d.TimeAttack
+	}
+}
+
+// Save writes c to the config file at path
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(path, data)
+}
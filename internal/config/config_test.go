@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gokana/internal/model"
+)
+
+// write writes content to a config file and returns its path
+func write(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gokana", "config.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Config{Settings: model.DefaultSettings()}) {
+		t.Fatalf("a missing file gave %+v, want the default settings", c)
+	}
+
+	c.Mode = model.ModeWords
+	c.SelectedKana = model.KanaTypeDeck
+	c.Deck = "Kanji"
+	c.Romanization = model.RomanizationHepburn
+	c.SelectedRows = map[model.KanaRow]bool{model.RowA: true, model.RowKa: true}
+	c.SpawnMode = model.SpawnAdaptive
+	c.StartLevel = 4
+	c.StartLives = 2
+	c.TimeAttack = model.TimeAttack60
+	c.Zen = true
+	if err := Save(path, c); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, c) {
+		t.Errorf("loaded %+v, saved %+v", loaded, c)
+	}
+}
+
+func TestLoadSanitizes(t *testing.T) {
+	c, err := Load(write(t, `{"mode": 99, "kana": -1, "romanization": 42, "spawn": 7, "level": 11, "lives": 0, "time_attack": 9}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Config{Settings: model.DefaultSettings()}) {
+		t.Errorf("out of range settings loaded as %+v, want the defaults", c)
+	}
+
+	// Rows left out of a saved selection stay unselected
+	c, err = Load(write(t, `{"rows": {"0": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.SelectedRows, map[model.KanaRow]bool{model.RowA: true}) {
+		t.Errorf("rows = %v, want only the a-row", c.SelectedRows)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := write(t, `{"mode": "words"}`)
+	c, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("error %v, want it to name the file", err)
+	}
+	if !reflect.DeepEqual(c, Config{Settings: model.DefaultSettings()}) {
+		t.Errorf("a bad file gave %+v, want the default settings", c)
+	}
+}
@@ -0,0 +1,173 @@
+package deck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gokana/internal/model"
+)
+
+// card is the format independent representation of a deck entry
+type card struct {
+	Character  string   `json:"character"`
+	Romaji     string   `json:"romaji"`
+	Alternates []string `json:"alternates"`
+	line       int
+}
+
+// file is the format independent representation of a deck file
+type file struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Cards       []card `json:"cards"`
+}
+
+// romajiPattern matches answers that can be typed during a game
+var romajiPattern = regexp.MustCompile(`^[a-z0-9'-]+$`)
+
+// Dir returns the directory custom decks are loaded from
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "gokana", "decks"), nil
+}
+
+// LoadAll loads every deck file in dir. Decks that fail to load are skipped
+// and reported in the returned errors. A missing dir is not an error.
+func LoadAll(dir string) ([]model.Deck, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isDeckFile(entry.Name()) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var decks []model.Deck
+	var errs []error
+	for _, name := range names {
+		d, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		decks = append(decks, d)
+	}
+	return decks, errs
+}
+
+// Load reads and validates a single deck file, picking the parser from its extension
+func Load(path string) (model.Deck, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.Deck{}, err
+	}
+
+	var f file
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		f, err = parseJSON(data)
+	case ".toml":
+		f, err = parseTOML(data)
+	case ".csv":
+		f, err = parseCSV(data)
+	default:
+		err = fmt.Errorf("unsupported deck format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return model.Deck{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	d, err := validate(f, path)
+	if err != nil {
+		return model.Deck{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return d, nil
+}
+
+func isDeckFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".toml", ".csv":
+		return true
+	default:
+		return false
+	}
+}
+
+// validate checks the parsed deck and converts it to a model.Deck
+func validate(f file, path string) (model.Deck, error) {
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(f.Cards) == 0 {
+		return model.Deck{}, fmt.Errorf("deck has no cards")
+	}
+
+	seen := make(map[string]bool)
+	kana := make([]model.Kana, 0, len(f.Cards))
+	for i, c := range f.Cards {
+		where := fmt.Sprintf("card %d", i+1)
+		if c.line > 0 {
+			where = fmt.Sprintf("line %d", c.line)
+		}
+
+		character := strings.TrimSpace(c.Character)
+		if character == "" {
+			return model.Deck{}, fmt.Errorf("%s: missing character", where)
+		}
+		if seen[character] {
+			return model.Deck{}, fmt.Errorf("%s: duplicate character %q", where, character)
+		}
+		seen[character] = true
+
+		romaji, err := normalizeRomaji(c.Romaji)
+		if err != nil {
+			return model.Deck{}, fmt.Errorf("%s: %q: %w", where, character, err)
+		}
+
+		var alternates []string
+		for _, a := range c.Alternates {
+			alternate, err := normalizeRomaji(a)
+			if err != nil {
+				return model.Deck{}, fmt.Errorf("%s: %q: alternate: %w", where, character, err)
+			}
+			alternates = append(alternates, alternate)
+		}
+
+		kana = append(kana, model.Kana{Character: character, Romaji: romaji, Alternates: alternates})
+	}
+
+	return model.Deck{
+		Name:        name,
+		Description: strings.TrimSpace(f.Description),
+		Path:        path,
+		Kana:        kana,
+	}, nil
+}
+
+func normalizeRomaji(romaji string) (string, error) {
+	romaji = strings.ToLower(strings.TrimSpace(romaji))
+	if romaji == "" {
+		return "", fmt.Errorf("missing romaji")
+	}
+	if !romajiPattern.MatchString(romaji) {
+		return "", fmt.Errorf("romaji %q may only contain letters, digits, ' and -", romaji)
+	}
+	return romaji, nil
+}
@@ -0,0 +1,168 @@
+package deck
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gokana/internal/model"
+)
+
+// load writes content to a deck file called name and loads it
+func load(t *testing.T, name, content string) (model.Deck, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+func TestLoad(t *testing.T) {
+	want := []model.Kana{
+		{Character: "日", Romaji: "nichi", Alternates: []string{"hi"}},
+		{Character: "月", Romaji: "tsuki"},
+	}
+
+	tests := []struct {
+		name, content string
+	}{
+		{"kanji.json", `{
+  "name": "Kanji",
+  "description": "A few kanji",
+  "cards": [
+    {"character": "日", "romaji": "nichi", "alternates": ["hi"]},
+    {"character": "月", "romaji": "tsuki"}
+  ]
+}`},
+		{"kanji.csv", `# name: Kanji
+# description: A few kanji
+character,romaji,alternates
+日,nichi,hi
+月, Tsuki
+`},
+		{"kanji.toml", `name = "Kanji" # shown in the menu
+description = 'A few kanji'
+
+[[cards]]
+character = "日"
+romaji = "nichi"
+alternates = ["hi"]
+
+[[cards]]
+character = "月"
+romaji = "tsuki"
+alternates = []
+`},
+	}
+	for _, tt := range tests {
+		d, err := load(t, tt.name, tt.content)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if d.Name != "Kanji" || d.Description != "A few kanji" {
+			t.Errorf("%s: name %q, description %q", tt.name, d.Name, d.Description)
+		}
+		if !slices.EqualFunc(d.Kana, want, func(a, b model.Kana) bool {
+			return a.Character == b.Character && a.Romaji == b.Romaji && slices.Equal(a.Alternates, b.Alternates)
+		}) {
+			t.Errorf("%s: kana = %v, want %v", tt.name, d.Kana, want)
+		}
+	}
+}
+
+func TestLoadTOMLStrings(t *testing.T) {
+	d, err := load(t, "quotes.toml", `name = "Say \"hi\" # not a comment" # a comment
+description = 'C:\decks # also not a comment'
+
+[[cards]]
+character = "日"
+romaji = "nichi"
+alternates = [
+  "hi", # the kun reading
+  'ka',
+]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != `Say "hi" # not a comment` {
+		t.Errorf("name = %q", d.Name)
+	}
+	if d.Description != `C:\decks # also not a comment` {
+		t.Errorf("description = %q", d.Description)
+	}
+	if got := d.Kana[0].Alternates; !slices.Equal(got, []string{"hi", "ka"}) {
+		t.Errorf("alternates = %v, want hi and ka", got)
+	}
+}
+
+func TestLoadNameFromFile(t *testing.T) {
+	d, err := load(t, "animals.csv", "犬,inu\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "animals" {
+		t.Errorf("name = %q, want the file name", d.Name)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, content, err string
+	}{
+		{"empty.json", `{"name": "Empty", "cards": []}`, "no cards"},
+		{"unknown.json", `{"cards": [{"character": "日", "romaji": "nichi", "kanji": true}]}`, "unknown field"},
+		{"syntax.json", "{\n\"cards\": [\n}", "line 3"},
+		{"short.csv", "日,nichi\n月\n", "line 2: expected character,romaji"},
+		{"duplicate.csv", "日,nichi\n日,hi\n", "line 2: duplicate character"},
+		{"romaji.csv", "日,にち\n", "may only contain"},
+		{"missing.csv", "日,\n", "missing romaji"},
+		{"table.toml", "[deck]\nname = \"x\"\n", "line 1: unexpected table"},
+		{"key.toml", "[[cards]]\ncharacter = \"日\"\nreading = \"nichi\"\n", "line 3: unknown card key"},
+		{"string.toml", "[[cards]]\ncharacter = 日\n", "line 2: character: expected a quoted string"},
+		{"inline.toml", "cards = [{character = \"日\", romaji = \"nichi\"}]\n", "line 1: inline cards are not supported"},
+		{"unterminated.toml", "[[cards]]\ncharacter = \"日\"\nalternates = [\n\"hi\",\n", "line 3: alternates: unterminated array"},
+		{"format.txt", "日,nichi\n", "unsupported deck format"},
+	}
+	for _, tt := range tests {
+		_, err := load(t, tt.name, tt.content)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want it to mention %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestLoadAll(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.csv":    "日,nichi\n",
+		"a.json":   `{"cards": [{"character": "月", "romaji": "tsuki"}]}`,
+		"bad.toml": "[[cards]]\n",
+		"notes.md": "not a deck",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decks, errs := LoadAll(dir)
+	var names []string
+	for _, d := range decks {
+		names = append(names, d.Name)
+	}
+	if !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("decks = %v, want a and b in file order", names)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "bad.toml") {
+		t.Errorf("errors = %v, want bad.toml", errs)
+	}
+
+	if decks, errs := LoadAll(filepath.Join(dir, "missing")); decks != nil || errs != nil {
+		t.Errorf("a missing directory gave %v, %v", decks, errs)
+	}
+}
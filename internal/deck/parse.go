@@ -0,0 +1,276 @@
+package deck
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// parseJSON reads a deck of the form
+//
+//	{"name": "...", "description": "...", "cards": [{"character": "日", "romaji": "nichi", "alternates": ["hi"]}]}
+func parseJSON(data []byte) (file, error) {
+	var f file
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return file{}, fmt.Errorf("line %d: %w", lineAt(data, syntaxErr.Offset), err)
+		}
+		return file{}, err
+	}
+	return f, nil
+}
+
+// parseCSV reads a deck with one card per line: character,romaji[,alternate...].
+// A leading "character,romaji" header is skipped, and the deck name and
+// description can be set with "# name: ..." and "# description: ..." comments.
+func parseCSV(data []byte) (file, error) {
+	var f file
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "#")), ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			f.Name = strings.TrimSpace(value)
+		case "description":
+			f.Description = strings.TrimSpace(value)
+		}
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return file{}, err
+		}
+		line, _ := r.FieldPos(0)
+		if len(f.Cards) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "character") {
+			continue
+		}
+		if len(record) < 2 {
+			return file{}, fmt.Errorf("line %d: expected character,romaji[,alternate...]", line)
+		}
+		c := card{Character: record[0], Romaji: record[1], line: line}
+		for _, a := range record[2:] {
+			if strings.TrimSpace(a) != "" {
+				c.Alternates = append(c.Alternates, a)
+			}
+		}
+		f.Cards = append(f.Cards, c)
+	}
+	return f, nil
+}
+
+// parseTOML reads the subset of TOML used by deck files:
+//
+//	name = "..."
+//	description = "..."
+//
+//	[[cards]]
+//	character = "日"
+//	romaji = "nichi"
+//	alternates = ["hi"]
+//
+// Values are basic or literal strings, and arrays of them that may span
+// several lines. Other tables, inline tables and dotted keys are rejected.
+func parseTOML(data []byte) (file, error) {
+	var f file
+	var current *card
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if line != "[[cards]]" {
+				return file{}, fmt.Errorf("line %d: unexpected table %s, only [[cards]] is supported", lineNumber, line)
+			}
+			f.Cards = append(f.Cards, card{line: lineNumber})
+			current = &f.Cards[len(f.Cards)-1]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return file{}, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		start := lineNumber
+		for strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") {
+			// A multi-line array goes on until its closing bracket
+			if !scanner.Scan() {
+				return file{}, fmt.Errorf("line %d: %s: unterminated array", start, key)
+			}
+			lineNumber++
+			value += " " + strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		}
+
+		if current == nil {
+			switch key {
+			case "name", "description":
+				s, err := parseTOMLString(value)
+				if err != nil {
+					return file{}, fmt.Errorf("line %d: %s: %w", start, key, err)
+				}
+				if key == "name" {
+					f.Name = s
+				} else {
+					f.Description = s
+				}
+			case "cards":
+				return file{}, fmt.Errorf("line %d: inline cards are not supported, use [[cards]] tables", start)
+			default:
+				return file{}, fmt.Errorf("line %d: unknown key %q", start, key)
+			}
+			continue
+		}
+
+		switch key {
+		case "character", "romaji":
+			s, err := parseTOMLString(value)
+			if err != nil {
+				return file{}, fmt.Errorf("line %d: %s: %w", start, key, err)
+			}
+			if key == "character" {
+				current.Character = s
+			} else {
+				current.Romaji = s
+			}
+		case "alternates":
+			list, err := parseTOMLStringArray(value)
+			if err != nil {
+				return file{}, fmt.Errorf("line %d: %s: %w", start, key, err)
+			}
+			current.Alternates = list
+		default:
+			return file{}, fmt.Errorf("line %d: unknown card key %q", start, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return file{}, err
+	}
+	return f, nil
+}
+
+// stripTOMLComment removes a trailing # comment that is not inside a string
+func stripTOMLComment(line string) string {
+	for i, r := range outsideTOMLStrings(line) {
+		if r == '#' {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// outsideTOMLStrings yields the byte offset of every rune of s that is not
+// part of a string. Basic "..." strings may contain escaped quotes, literal
+// '...' strings have no escapes.
+func outsideTOMLStrings(s string) iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		var quote rune
+		escaped := false
+		for i, r := range s {
+			switch {
+			case escaped:
+				escaped = false
+			case quote == '"' && r == '\\':
+				escaped = true
+			case quote != 0:
+				if r == quote {
+					quote = 0
+				}
+			case r == '"' || r == '\'':
+				quote = r
+			default:
+				if !yield(i, r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func parseTOMLString(value string) (string, error) {
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", value)
+		}
+		return s, nil
+	}
+	return "", fmt.Errorf("expected a quoted string, got %s", value)
+}
+
+func parseTOMLStringArray(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("expected an array of strings, got %s", value)
+	}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return nil, nil
+	}
+
+	var items []string
+	start := 0
+	for i, r := range outsideTOMLStrings(inner) {
+		if r == ',' {
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, inner[start:])
+
+	var list []string
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		s, err := parseTOMLString(item)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// lineAt returns the 1-based line number of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
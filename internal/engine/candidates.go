@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"gokana/internal/model"
+)
+
+// minCandidates is the number of choices offered in reverse mode when only
+// a few kana are falling, the rest are distractors from the kana pool
+const minCandidates = 4
+
+// maxCandidates is the number of choices reachable with the 1-9 keys
+const maxCandidates = 9
+
+// refreshCandidates rebuilds the numbered choices for reverse mode when a
+// falling kana is missing from them, so they stay put while nothing changes.
+// Choices are told apart by their romaji: kana showing the same romaji are
+// interchangeable answers, so only one of them is offered.
+func refreshCandidates(m *model.Model) {
+	present := make(map[string]bool, len(m.Candidates))
+	for _, c := range m.Candidates {
+		present[m.Label(c)] = true
+	}
+	upToDate := len(m.Candidates) > 0
+	for _, fk := range m.FallingKanas {
+		if !fk.ShowingCorrect && !present[m.Label(fk.Kana)] {
+			upToDate = false
+			break
+		}
+	}
+	if upToDate {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var candidates []model.Kana
+	for _, fk := range m.FallingKanas {
+		label := m.Label(fk.Kana)
+		if !seen[label] && len(candidates) < maxCandidates {
+			seen[label] = true
+			candidates = append(candidates, fk.Kana)
+		}
+	}
+
+	pool := m.KanaPool()
+	target := min(max(minCandidates, len(candidates)+2), maxCandidates)
+	for _, i := range m.Rand.Perm(len(pool)) {
+		if len(candidates) >= target {
+			break
+		}
+		if label := m.Label(pool[i]); !seen[label] {
+			seen[label] = true
+			candidates = append(candidates, pool[i])
+		}
+	}
+
+	m.Rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	m.Candidates = candidates
+}
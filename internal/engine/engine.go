@@ -0,0 +1,159 @@
+// Package engine holds the rules of a run independently of the terminal:
+// time only moves through Step, input only arrives through Submit and
+// Choose, and everything that happens is reported as typed events.
+package engine
+
+import (
+	"math/rand"
+	"time"
+
+	"gokana/internal/model"
+)
+
+// Clock tells the engine the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+type SystemClock struct{}
+
+// Now returns the current wall clock time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Engine runs the game rules on a model
+type Engine struct {
+	M     *model.Model
+	Clock Clock
+	// Source is reseeded by Start for every run, every kana and position is drawn from it
+	Source rand.Source
+
+	// Recording collects every call made during the current run
+	Recording *Recording
+}
+
+// New returns an engine running on m, reading the time from clock and
+// drawing from source, the math/rand source if nil
+func New(m *model.Model, clock Clock, source rand.Source) *Engine {
+	if source == nil {
+		source = rand.NewSource(1)
+	}
+	return &Engine{M: m, Clock: clock, Source: source}
+}
+
+// now reads the clock and keeps it on the model, so durations computed from
+// the model agree with the engine
+func (e *Engine) now() time.Time {
+	e.M.Now = e.Clock.Now()
+	return e.M.Now
+}
+
+// Start starts a new run with the current settings, drawing every kana and
+// position from the engine's source seeded with seed
+func (e *Engine) Start(seed int64) []Event {
+	m := e.M
+	m.Seed = seed
+	e.Source.Seed(seed)
+	m.Rand = rand.New(e.Source)
+
+	startLevel := m.StartLevel
+	if startLevel < 1 {
+		startLevel = 1
+	}
+	if startLevel > 10 {
+		startLevel = 10
+	}
+
+	// Calculate speed based on level
+	speed := time.Millisecond * 700
+	for i := 1; i < startLevel; i++ {
+		speed = time.Duration(float64(speed) * 0.85)
+		if speed < time.Millisecond*100 {
+			speed = time.Millisecond * 100
+			break
+		}
+	}
+
+	m.State = model.StatePlaying
+	m.FallSpeed = speed
+	m.Correct = 0
+	m.Total = 0
+	m.WrongInputs = 0
+	m.Streak = 0
+	m.MaxStreak = 0
+	m.Points = 0
+	m.Mistakes = nil
+	m.StartedAt = e.now()
+	m.EndedAt = time.Time{}
+	m.PausedTotal = 0
+	m.LevelOffset = startLevel - 1
+	m.Lives = m.StartLives
+	m.GameOver = false
+	m.Quitting = false
+	m.Input = ""
+	m.Feedback = ""
+	m.FeedbackType = ""
+	m.ShowingFeedback = false
+	m.TimeAccumulated = 0
+	m.FallingKanas = []model.FallingKana{}
+	m.Candidates = nil
+	e.startRecording(m.StartedAt)
+
+	if m.Mode == model.ModeQuiz {
+		// The quiz asks one kana at a time instead of dropping them
+		e.nextQuestion()
+		return []Event{Spawned{Kana: m.QuizQuestion}}
+	}
+
+	// Spawn initial kanas based on level
+	events := e.fill()
+	if m.Mode == model.ModeReverse {
+		refreshCandidates(m)
+	}
+	return events
+}
+
+// Pause freezes the run until Resume
+func (e *Engine) Pause() {
+	e.M.State = model.StatePaused
+	e.M.PausedAt = e.now()
+	e.record(Input{Op: OpPause})
+}
+
+// Resume continues a paused run, leaving the paused time out of the run
+// duration and of the kana answer times
+func (e *Engine) Resume() {
+	m := e.M
+	now := e.now()
+	e.record(Input{Op: OpResume})
+	paused := now.Sub(m.PausedAt)
+	m.PausedTotal += paused
+	for i := range m.FallingKanas {
+		if m.FallingKanas[i].SpawnedAt.Before(m.PausedAt) {
+			m.FallingKanas[i].SpawnedAt = m.FallingKanas[i].SpawnedAt.Add(paused)
+		} else {
+			m.FallingKanas[i].SpawnedAt = now
+		}
+	}
+	m.QuizQuestion.SpawnedAt = m.QuizQuestion.SpawnedAt.Add(paused)
+	m.State = model.StatePlaying
+}
+
+// End stops the run
+func (e *Engine) End() []Event {
+	e.now()
+	e.record(Input{Op: OpEnd})
+	return e.end()
+}
+
+// end stops the run, whether the player ended it or the rules did
+func (e *Engine) end() []Event {
+	m := e.M
+	m.GameOver = true
+	m.State = model.StateGameOver
+	m.EndedAt = m.Now
+	m.Input = ""
+	return []Event{GameOver{}}
+}
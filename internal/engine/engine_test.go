@@ -0,0 +1,328 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"gokana/internal/model"
+)
+
+// fakeClock is a clock the tests move by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// newTestEngine returns an engine on a 40x10 field with the default
+// settings changed by configure, started with seed
+func newTestEngine(seed int64, configure func(s *model.Settings)) (*Engine, *fakeClock) {
+	s := model.DefaultSettings()
+	if configure != nil {
+		configure(&s)
+	}
+	m := &model.Model{
+		FallingKanas:  []model.FallingKana{},
+		PlayAreaWidth: 40,
+		MaxFallHeight: 10,
+	}
+	m.ApplySettings(s)
+
+	c := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	e := New(m, c, nil)
+	e.Start(seed)
+	return e, c
+}
+
+// step moves the clock by dt and steps the engine
+func step(e *Engine, c *fakeClock, dt time.Duration) []Event {
+	c.now = c.now.Add(dt)
+	return e.Step(dt)
+}
+
+// fall steps the engine one row at a time until the kana reach the bottom
+func fall(e *Engine, c *fakeClock) []Event {
+	var events []Event
+	for range e.M.MaxFallHeight {
+		events = append(events, step(e, c, e.M.FallSpeed)...)
+	}
+	return events
+}
+
+// answer types the answer of the first falling kana not answered yet
+func answer(e *Engine) []Event {
+	for _, fk := range e.M.FallingKanas {
+		if !fk.ShowingCorrect {
+			return e.Submit(fk.Kana.Romaji)
+		}
+	}
+	return nil
+}
+
+// eventsOf returns the events of type T
+func eventsOf[T Event](events []Event) []T {
+	var found []T
+	for _, event := range events {
+		if event, ok := event.(T); ok {
+			found = append(found, event)
+		}
+	}
+	return found
+}
+
+func TestStartSpawnsByLevel(t *testing.T) {
+	e, _ := newTestEngine(1, func(s *model.Settings) { s.StartLevel = 3 })
+	if got := len(e.M.FallingKanas); got != 3 {
+		t.Errorf("level 3 starts with %d kana, want 3", got)
+	}
+	if e.M.State != model.StatePlaying {
+		t.Errorf("state = %v, want playing", e.M.State)
+	}
+}
+
+func TestSameSeedSameSpawns(t *testing.T) {
+	spawns := func(seed int64) []model.FallingKana {
+		e, c := newTestEngine(seed, func(s *model.Settings) { s.Zen = true; s.StartLevel = 3 })
+		var falling []model.FallingKana
+		falling = append(falling, e.M.FallingKanas...)
+		for range 5 {
+			answer(e)
+			e.Settle()
+			for _, event := range eventsOf[Spawned](fall(e, c)) {
+				falling = append(falling, event.Kana)
+			}
+			e.ClearFeedback()
+		}
+		return falling
+	}
+
+	a, b := spawns(42), spawns(42)
+	if len(a) == 0 {
+		t.Fatal("no kana spawned")
+	}
+	same := func(x, y model.FallingKana) bool {
+		return x.Kana.Character == y.Kana.Character && x.HorizontalPos == y.HorizontalPos
+	}
+	if !slices.EqualFunc(a, b, same) {
+		t.Errorf("seed 42 spawned differently:\n%v\n%v", a, b)
+	}
+	if slices.EqualFunc(a, spawns(43), same) {
+		t.Error("seeds 42 and 43 spawned the same kana")
+	}
+}
+
+// countingSource is a random source recording its seed and draws
+type countingSource struct {
+	rand.Source
+	seed  int64
+	draws int
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.seed = seed
+	s.Source.Seed(seed)
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.Source.Int63()
+}
+
+func TestStartSeedsSource(t *testing.T) {
+	e, _ := newTestEngine(1, func(s *model.Settings) { s.StartLevel = 3 })
+	source := &countingSource{Source: rand.NewSource(0)}
+	e.Source = source
+	e.Start(42)
+	if source.seed != 42 || source.draws == 0 {
+		t.Fatalf("source seeded with %d, %d draws, want seed 42 and the spawns drawn from it", source.seed, source.draws)
+	}
+
+	want, _ := newTestEngine(42, func(s *model.Settings) { s.StartLevel = 3 })
+	same := func(x, y model.FallingKana) bool {
+		return x.Kana.Character == y.Kana.Character && x.HorizontalPos == y.HorizontalPos
+	}
+	if !slices.EqualFunc(e.M.FallingKanas, want.M.FallingKanas, same) {
+		t.Errorf("spawned %v with the injected source, want %v", e.M.FallingKanas, want.M.FallingKanas)
+	}
+}
+
+func TestSubmitCorrect(t *testing.T) {
+	e, c := newTestEngine(1, nil)
+	fk := e.M.FallingKanas[0]
+	c.now = c.now.Add(2 * time.Second)
+
+	events := e.Submit(fk.Kana.Romaji)
+	correct := eventsOf[Correct](events)
+	if len(correct) != 1 {
+		t.Fatalf("events = %v, want one Correct", events)
+	}
+	if correct[0].Kana.Kana.Character != fk.Kana.Character || correct[0].Elapsed != 2*time.Second {
+		t.Errorf("Correct = %+v, want %s after 2s", correct[0], fk.Kana.Character)
+	}
+	if e.M.Correct != 1 || e.M.Streak != 1 || e.M.Points == 0 {
+		t.Errorf("correct %d, streak %d, points %d after a correct answer", e.M.Correct, e.M.Streak, e.M.Points)
+	}
+
+	spawned := eventsOf[Spawned](e.Settle())
+	if len(spawned) != 1 || len(e.M.FallingKanas) != 1 || e.M.FallingKanas[0].ShowingCorrect {
+		t.Errorf("settle did not replace the answered kana: %v", e.M.FallingKanas)
+	}
+}
+
+func TestSubmitWrong(t *testing.T) {
+	e, _ := newTestEngine(1, nil)
+
+	// No romaji starts with q
+	wrong := eventsOf[Wrong](e.Submit("q"))
+	if len(wrong) != 1 || wrong[0].Input != "q" {
+		t.Fatalf("wrong = %v, want one Wrong for q", wrong)
+	}
+	if e.M.WrongInputs != 1 || !e.M.ShowingFeedback || e.M.Input != "" {
+		t.Errorf("wrong inputs %d, feedback %v, input %q after a wrong input", e.M.WrongInputs, e.M.ShowingFeedback, e.M.Input)
+	}
+	if events := answer(e); events != nil {
+		t.Errorf("input was taken during the feedback: %v", events)
+	}
+
+	e.ClearFeedback()
+	if len(eventsOf[Correct](answer(e))) != 1 {
+		t.Error("input was not taken after the feedback")
+	}
+}
+
+func TestStepMissed(t *testing.T) {
+	e, c := newTestEngine(1, nil)
+	fk := e.M.FallingKanas[0]
+
+	if events := step(e, c, e.M.FallSpeed/2); events != nil {
+		t.Errorf("events before a full row: %v", events)
+	}
+	events := fall(e, c)
+	missed := eventsOf[Missed](events)
+	if len(missed) != 1 || missed[0].Kana.Kana.Character != fk.Kana.Character {
+		t.Fatalf("events = %v, want %s missed", events, fk.Kana.Character)
+	}
+	if len(eventsOf[Spawned](events)) != 1 {
+		t.Errorf("events = %v, want a kana spawned in place of the missed one", events)
+	}
+	if e.M.Lives != e.M.StartLives-1 || e.M.Streak != 0 {
+		t.Errorf("lives %d, streak %d after a miss", e.M.Lives, e.M.Streak)
+	}
+}
+
+func TestStepGameOver(t *testing.T) {
+	e, c := newTestEngine(1, func(s *model.Settings) { s.StartLives = 1 })
+
+	events := fall(e, c)
+	if len(eventsOf[Missed](events)) != 1 || len(eventsOf[GameOver](events)) != 1 {
+		t.Fatalf("events = %v, want a miss ending the run", events)
+	}
+	if e.M.State != model.StateGameOver || e.M.EndedAt.IsZero() {
+		t.Errorf("state %v, ended %v after the last life", e.M.State, e.M.EndedAt)
+	}
+	if events := step(e, c, e.M.FallSpeed); events != nil {
+		t.Errorf("events after the game over: %v", events)
+	}
+}
+
+func TestLevelUp(t *testing.T) {
+	e, _ := newTestEngine(1, nil)
+
+	var levels []LevelUp
+	for range 20 {
+		levels = append(levels, eventsOf[LevelUp](answer(e))...)
+		e.Settle()
+	}
+	if len(levels) != 1 || levels[0].Level != 2 {
+		t.Fatalf("level ups = %v, want level 2 after 20 answers", levels)
+	}
+	if got := len(e.M.FallingKanas); got != 2 {
+		t.Errorf("%d kana falling at level 2, want 2", got)
+	}
+}
+
+func TestTimeAttackEnds(t *testing.T) {
+	e, c := newTestEngine(1, func(s *model.Settings) { s.TimeAttack = model.TimeAttack60 })
+
+	c.now = c.now.Add(59 * time.Second)
+	if events := eventsOf[GameOver](e.Step(time.Millisecond)); len(events) != 0 {
+		t.Fatal("the run ended before the time limit")
+	}
+	c.now = c.now.Add(time.Second)
+	if events := eventsOf[GameOver](e.Step(time.Millisecond)); len(events) != 1 {
+		t.Fatal("the run did not end on the time limit")
+	}
+	if e.M.Lives != e.M.StartLives {
+		t.Errorf("lives = %d, a time attack has no lives to lose", e.M.Lives)
+	}
+}
+
+func TestZenRecyclesMissedKana(t *testing.T) {
+	e, c := newTestEngine(1, func(s *model.Settings) { s.Zen = true; s.StartLives = 1 })
+	fk := e.M.FallingKanas[0]
+
+	events := fall(e, c)
+	spawned := eventsOf[Spawned](events)
+	if len(eventsOf[Missed](events)) != 1 || len(spawned) != 1 {
+		t.Fatalf("events = %v, want a miss and a spawn", events)
+	}
+	if spawned[0].Kana.Kana.Character != fk.Kana.Character || spawned[0].Kana.FallPosition != 0 {
+		t.Errorf("spawned %+v, want %s back at the top", spawned[0].Kana, fk.Kana.Character)
+	}
+	if e.M.State != model.StatePlaying || e.M.Lives != 1 {
+		t.Errorf("state %v, lives %d, zen misses cost no life", e.M.State, e.M.Lives)
+	}
+}
+
+func TestSpawnNeverOverlaps(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(s *model.Settings)
+	}{
+		{"words", func(s *model.Settings) { s.Mode = model.ModeWords }},
+		{"yoon", func(s *model.Settings) { s.YoonEnabled = true }},
+		{"zen words", func(s *model.Settings) { s.Mode = model.ModeWords; s.Zen = true }},
+	}
+	for _, tt := range tests {
+		e, c := newTestEngine(1, func(s *model.Settings) {
+			tt.configure(s)
+			s.StartLevel = 10
+			s.StartLives = 10
+		})
+		m := e.M
+		// A 50 column terminal
+		e.SetField(47, 8)
+
+		for i := range 500 {
+			if i%7 == 0 {
+				answer(e)
+				e.Settle()
+			}
+			step(e, c, m.FallSpeed)
+			e.ClearFeedback()
+			if m.GameOver {
+				break
+			}
+
+			for a, x := range m.FallingKanas {
+				if x.HorizontalPos < 0 || x.HorizontalPos+m.LabelWidth(x.Kana) > m.PlayAreaWidth {
+					t.Fatalf("%s: %s at %d does not fit in %d cells", tt.name, x.Kana.Character, x.HorizontalPos, m.PlayAreaWidth)
+				}
+				for _, y := range m.FallingKanas[a+1:] {
+					if x.FallPosition == y.FallPosition &&
+						x.HorizontalPos < y.HorizontalPos+m.LabelWidth(y.Kana) &&
+						y.HorizontalPos < x.HorizontalPos+m.LabelWidth(x.Kana) {
+						t.Fatalf("%s: step %d: %s and %s overlap on row %d", tt.name, i, x.Kana.Character, y.Kana.Character, x.FallPosition)
+					}
+				}
+			}
+		}
+		if got := len(m.FallingKanas); got != m.FieldSize() {
+			t.Errorf("%s: %d kana falling, the level asks for %d", tt.name, got, m.FieldSize())
+		}
+	}
+}
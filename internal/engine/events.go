@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"time"
+
+	"gokana/internal/model"
+)
+
+// Event is something that happened during a run. The engine returns them
+// from every call so the caller can react: persist statistics, schedule
+// animations, record a replay...
+type Event interface {
+	event()
+}
+
+// Spawned is a kana entering the play area, or the next quiz question
+type Spawned struct {
+	Kana model.FallingKana
+}
+
+// Correct is a kana answered correctly, Elapsed after it spawned
+type Correct struct {
+	Kana    model.FallingKana
+	Elapsed time.Duration
+}
+
+// Wrong is input that cannot lead to any falling kana's answer, attributed
+// to the kana the player was most likely typing. Answered is set when the
+// input was a final answer (a wrong quiz pick) rather than a wrong prefix.
+type Wrong struct {
+	Kana     model.FallingKana
+	Input    string
+	Answered bool
+}
+
+// Missed is a kana that reached the bottom without being answered
+type Missed struct {
+	Kana model.FallingKana
+}
+
+// LevelUp is the run reaching a new level
+type LevelUp struct {
+	Level int
+}
+
+// GameOver is the end of the run
+type GameOver struct{}
+
+func (Spawned) event()  {}
+func (Correct) event()  {}
+func (Wrong) event()    {}
+func (Missed) event()   {}
+func (LevelUp) event()  {}
+func (GameOver) event() {}
@@ -0,0 +1,21 @@
+package engine
+
+// SetField resizes the play field to width columns and height rows, moving
+// the falling kana proportionally
+func (e *Engine) SetField(width, height int) {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpField, Width: width, Height: height})
+
+	oldWidth, oldHeight := m.PlayAreaWidth, m.MaxFallHeight
+	for i := range m.FallingKanas {
+		fk := &m.FallingKanas[i]
+		fk.HorizontalPos = fk.HorizontalPos * width / oldWidth
+		fk.FallPosition = fk.FallPosition * height / oldHeight
+		fk.HorizontalPos = max(min(fk.HorizontalPos, width-m.LabelWidth(fk.Kana)), 0)
+		fk.FallPosition = min(fk.FallPosition, height-1)
+	}
+
+	m.PlayAreaWidth = width
+	m.MaxFallHeight = height
+}
@@ -0,0 +1,363 @@
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"gokana/internal/model"
+)
+
+// Step advances the run by dt: the kana fall one row every FallSpeed, the
+// ones reaching the bottom are missed, and a time attack ends on time
+func (e *Engine) Step(dt time.Duration) []Event {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpStep, Dt: dt})
+	if m.State != model.StatePlaying || m.GameOver {
+		return nil
+	}
+	if m.Timed() && m.Remaining() == 0 {
+		return e.end()
+	}
+	if m.Mode == model.ModeQuiz {
+		return nil
+	}
+
+	m.TimeAccumulated += dt
+	if m.TimeAccumulated < m.FallSpeed {
+		return nil
+	}
+	m.TimeAccumulated -= m.FallSpeed
+
+	var events []Event
+	var recycled []model.FallingKana
+	newFalling := []model.FallingKana{}
+	for _, fk := range m.FallingKanas {
+		if fk.ShowingCorrect {
+			newFalling = append(newFalling, fk)
+			continue
+		}
+
+		fk.FallPosition++
+		if fk.FallPosition < m.MaxFallHeight {
+			newFalling = append(newFalling, fk)
+			continue
+		}
+
+		m.RecordMistake(fk.Kana, true)
+		m.Total++
+		m.Feedback = m.Label(fk.Kana) + " = " + m.AnswerText(fk.Kana)
+		m.Streak = 0
+		events = append(events, Missed{Kana: fk})
+		if m.LivesEnabled() {
+			m.Lives--
+			if m.Lives <= 0 {
+				return e.review(append(events, e.end()...))
+			}
+		}
+		m.FeedbackType = "wrong"
+		m.ShowingFeedback = true
+		m.Input = ""
+		if m.Zen {
+			recycled = append(recycled, fk)
+		}
+	}
+	m.FallingKanas = newFalling
+
+	// Zen mode sends the missed kana around again, on a full top row they
+	// give way to fresh kana spawned once there is room
+	for _, fk := range recycled {
+		if next, ok := e.recycleKana(fk); ok {
+			m.FallingKanas = append(m.FallingKanas, next)
+			events = append(events, Spawned{Kana: next})
+		}
+	}
+	events = append(events, e.fill()...)
+
+	if m.Mode == model.ModeReverse {
+		refreshCandidates(m)
+	}
+	return e.review(events)
+}
+
+// Submit types input after what was already typed and checks it against the
+// falling kana
+func (e *Engine) Submit(input string) []Event {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpSubmit, Text: input})
+	if !e.acceptsInput() || m.Mode == model.ModeQuiz {
+		return nil
+	}
+	if e.spellsCorrect(m.Input + input) {
+		// Still typing a longer spelling of the kana just answered, the second n of nn
+		m.Input += input
+		return nil
+	}
+	events := e.clearCorrect()
+	m.Input += input
+	return e.review(append(events, e.checkInput()...))
+}
+
+// Choose picks a numbered option: a quiz answer, or in reverse mode a
+// candidate kana submitted as the whole answer
+func (e *Engine) Choose(index int) []Event {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpChoose, Index: index})
+	if !e.acceptsInput() {
+		return nil
+	}
+	if m.Mode == model.ModeQuiz {
+		return e.review(e.answerQuiz(index))
+	}
+	if index < 0 || index >= len(m.Candidates) {
+		return nil
+	}
+	events := e.clearCorrect()
+	m.Input = m.Candidates[index].Character
+	return e.review(append(events, e.checkInput()...))
+}
+
+// Backspace removes the last typed character
+func (e *Engine) Backspace() []Event {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpBackspace})
+	if !e.acceptsInput() {
+		return nil
+	}
+	if m.HasShowingCorrect() {
+		return e.clearCorrect()
+	}
+	if len(m.Input) > 0 {
+		// Trim a whole rune, kana typed in reverse mode are multi-byte
+		runes := []rune(m.Input)
+		m.Input = string(runes[:len(runes)-1])
+		m.Feedback = ""
+	}
+	return nil
+}
+
+// Settle ends the correct answer animation: answered kana leave the play
+// area and new ones take their place, or the quiz moves to the next question
+func (e *Engine) Settle() []Event {
+	m := e.M
+	e.now()
+	e.record(Input{Op: OpSettle})
+	if m.Mode == model.ModeQuiz {
+		if m.QuizAnswer == -1 {
+			return nil
+		}
+		e.nextQuestion()
+		return []Event{Spawned{Kana: m.QuizQuestion}}
+	}
+
+	if !m.HasShowingCorrect() {
+		// Typing already cleared the answered kana
+		return nil
+	}
+	events := e.removeCorrect()
+	m.Input = ""
+	m.TimeAccumulated = 0
+	return events
+}
+
+// ClearFeedback hides the feedback shown after a miss or a wrong input
+func (e *Engine) ClearFeedback() {
+	e.now()
+	e.record(Input{Op: OpClearFeedback})
+	e.M.ShowingFeedback = false
+	e.M.Feedback = ""
+	e.M.FeedbackType = ""
+}
+
+// review updates the spaced-repetition strength of the kana answered or
+// missed in events, adaptive spawning draws from the updated weights
+func (e *Engine) review(events []Event) []Event {
+	m := e.M
+	if m.Scheduler == nil {
+		return events
+	}
+	for _, event := range events {
+		switch event := event.(type) {
+		case Correct:
+			m.Scheduler.Review(event.Kana.Kana.Character, true, m.Now)
+		case Missed:
+			m.Scheduler.Review(event.Kana.Kana.Character, false, m.Now)
+		case Wrong:
+			if event.Kana.Kana.Character != "" {
+				m.Scheduler.Review(event.Kana.Kana.Character, false, m.Now)
+			}
+		}
+	}
+	return events
+}
+
+// acceptsInput checks if the run currently takes answers, not while the
+// feedback of a mistake is shown
+func (e *Engine) acceptsInput() bool {
+	m := e.M
+	return m.State == model.StatePlaying && !m.GameOver && !m.ShowingFeedback
+}
+
+// clearCorrect starts over from an empty input once a kana was answered.
+// The answered kana leave right away rather than becoming answerable again.
+func (e *Engine) clearCorrect() []Event {
+	m := e.M
+	if !m.HasShowingCorrect() {
+		return nil
+	}
+	m.Input = ""
+	return e.removeCorrect()
+}
+
+// spellsCorrect checks if input can still spell a kana that was just answered
+func (e *Engine) spellsCorrect(input string) bool {
+	m := e.M
+	answer := strings.TrimSpace(strings.ToLower(input))
+	for _, fk := range m.FallingKanas {
+		if fk.ShowingCorrect && m.HasAnswerPrefix(fk.Kana, answer) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeCorrect takes the answered kana out of the play area and spawns new
+// ones in their place
+func (e *Engine) removeCorrect() []Event {
+	m := e.M
+	newFalling := []model.FallingKana{}
+	for _, fk := range m.FallingKanas {
+		if !fk.ShowingCorrect {
+			newFalling = append(newFalling, fk)
+		}
+	}
+	m.FallingKanas = newFalling
+
+	events := e.fill()
+	if m.Mode == model.ModeReverse {
+		refreshCandidates(m)
+	}
+	return events
+}
+
+// fill spawns kana until the play area holds as many as the level asks for,
+// or the top row is full and the rest wait for the next step
+func (e *Engine) fill() []Event {
+	m := e.M
+	var events []Event
+	for len(m.FallingKanas) < m.FieldSize() {
+		fk, ok := e.SpawnKana()
+		if !ok {
+			break
+		}
+		m.FallingKanas = append(m.FallingKanas, fk)
+		events = append(events, Spawned{Kana: fk})
+	}
+	return events
+}
+
+// checkInput answers the falling kana the input matches, or rejects input
+// that cannot lead to any of their answers
+func (e *Engine) checkInput() []Event {
+	m := e.M
+	answer := strings.TrimSpace(strings.ToLower(m.Input))
+
+	matchedIndex := -1
+	isValidPrefix := false
+	for i, fk := range m.FallingKanas {
+		if m.Accepts(fk.Kana, answer) {
+			matchedIndex = i
+			isValidPrefix = true
+			break
+		}
+		if m.HasAnswerPrefix(fk.Kana, answer) {
+			isValidPrefix = true
+		}
+	}
+
+	var events []Event
+	switch {
+	case matchedIndex != -1:
+		fk := m.FallingKanas[matchedIndex]
+		events = append(events, Correct{Kana: fk, Elapsed: m.Now.Sub(fk.SpawnedAt)})
+		m.Total++
+		m.Correct++
+		m.Streak++
+		if m.Streak > m.MaxStreak {
+			m.MaxStreak = m.Streak
+		}
+		m.Points += m.AnswerPoints(fk)
+		m.FeedbackType = "correct"
+		m.FallingKanas[matchedIndex].ShowingCorrect = true
+		m.TimeAccumulated = 0
+
+		if m.Correct%20 == 0 && !m.Zen {
+			m.FallSpeed = time.Duration(float64(m.FallSpeed) * 0.85)
+			if m.FallSpeed < time.Millisecond*100 {
+				m.FallSpeed = time.Millisecond * 100
+			}
+			events = append(events, LevelUp{Level: m.GetLevel()})
+			events = append(events, e.fill()...)
+		}
+
+	case !isValidPrefix:
+		wrong := Wrong{Input: answer}
+		if target := wrongTarget(m, answer); target != -1 {
+			wrong.Kana = m.FallingKanas[target]
+			m.RecordMistake(wrong.Kana.Kana, false)
+		}
+		events = append(events, wrong)
+		m.WrongInputs++
+		m.Streak = 0
+		m.FeedbackType = "wrong"
+		m.ShowingFeedback = true
+		m.Input = ""
+
+	default:
+		m.FeedbackType = ""
+	}
+
+	if m.Mode == model.ModeReverse {
+		refreshCandidates(m)
+	}
+	return events
+}
+
+// wrongTarget returns the falling kana the player was most likely typing:
+// the one sharing the longest prefix with the input, the lowest one on
+// screen when tied. It is -1 when the input shares no prefix with any
+// answer, a stray key is nobody's mistake.
+func wrongTarget(m *model.Model, input string) int {
+	target := -1
+	best := 0
+	for i, fk := range m.FallingKanas {
+		if fk.ShowingCorrect {
+			continue
+		}
+		common := 0
+		for _, a := range m.Answers(fk.Kana) {
+			if n := commonPrefixLen(a, input); n > common {
+				common = n
+			}
+		}
+		if common > best || (common == best && target != -1 && fk.FallPosition > m.FallingKanas[target].FallPosition) {
+			target = i
+			best = common
+		}
+	}
+	return target
+}
+
+// commonPrefixLen counts the characters a and b start with, in runes so
+// kana sharing their UTF-8 lead bytes don't count as a common prefix
+func commonPrefixLen(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	n := 0
+	for n < len(ra) && n < len(rb) && ra[n] == rb[n] {
+		n++
+	}
+	return n
+}
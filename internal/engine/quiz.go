@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"strings"
+
+	"gokana/internal/model"
+)
+
+// quizOptions is the number of romaji choices offered per question
+const quizOptions = 4
+
+// nextQuestion picks a new kana and its shuffled answer options
+func (e *Engine) nextQuestion() {
+	m := e.M
+	kana := e.pickKana(m.KanaPool())
+	m.QuizQuestion = model.FallingKana{Kana: kana, SpawnedAt: e.now()}
+	m.QuizAnswer = -1
+
+	options := append([]model.Kana{kana}, quizDistractors(m, kana, quizOptions-1)...)
+	m.Rand.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	m.QuizOptions = options
+}
+
+// quizDistractors picks wrong options, preferring the kana's known
+// confusables, then kana whose romaji share its consonant or vowel, so the
+// answer cannot be guessed by elimination
+func quizDistractors(m *model.Model, kana model.Kana, n int) []model.Kana {
+	answer := m.AnswerText(kana)
+	seen := map[string]bool{answer: true}
+
+	pool := m.KanaPool()
+	confusables := model.ConfusablesIn(kana.Character, pool)
+	m.Rand.Shuffle(len(confusables), func(i, j int) {
+		confusables[i], confusables[j] = confusables[j], confusables[i]
+	})
+
+	var confusing, similar, others []model.Kana
+	for _, k := range confusables {
+		if text := m.AnswerText(k); !seen[text] {
+			seen[text] = true
+			confusing = append(confusing, k)
+		}
+	}
+	for _, i := range m.Rand.Perm(len(pool)) {
+		k := pool[i]
+		text := m.AnswerText(k)
+		if seen[text] {
+			continue
+		}
+		seen[text] = true
+		if soundsAlike(answer, text) {
+			similar = append(similar, k)
+		} else {
+			others = append(others, k)
+		}
+	}
+
+	distractors := append(append(confusing, similar...), others...)
+	if len(distractors) > n {
+		distractors = distractors[:n]
+	}
+	return distractors
+}
+
+// soundsAlike checks if two romaji share their consonant or their vowel
+func soundsAlike(a, b string) bool {
+	consonant := func(s string) string {
+		return strings.TrimRight(s, "aiueo")
+	}
+	return consonant(a) == consonant(b) || a[len(a)-1] == b[len(b)-1]
+}
+
+// answerQuiz picks one of the quiz options
+func (e *Engine) answerQuiz(index int) []Event {
+	m := e.M
+	if m.QuizAnswer != -1 || index < 0 || index >= len(m.QuizOptions) {
+		return nil
+	}
+	m.QuizAnswer = index
+	m.Total++
+
+	question := m.QuizQuestion
+	if m.QuizOptions[index].Character == question.Kana.Character {
+		m.Correct++
+		m.Streak++
+		if m.Streak > m.MaxStreak {
+			m.MaxStreak = m.Streak
+		}
+		m.Points += m.AnswerPoints(question)
+		m.FeedbackType = "correct"
+		return []Event{Correct{Kana: question, Elapsed: e.now().Sub(question.SpawnedAt)}}
+	}
+
+	m.RecordMistake(question.Kana, false)
+	m.Streak = 0
+	m.FeedbackType = "wrong"
+	m.ShowingFeedback = true
+	m.Feedback = question.Kana.Character + " = " + m.AnswerText(question.Kana)
+	return []Event{Wrong{Kana: question, Input: m.AnswerText(m.QuizOptions[index]), Answered: true}}
+}
@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"time"
+
+	"gokana/internal/model"
+	"gokana/internal/srs"
+)
+
+// Op is the kind of call made to the engine
+type Op string
+
+const (
+	OpStep          Op = "step"
+	OpSubmit        Op = "submit"
+	OpChoose        Op = "choose"
+	OpBackspace     Op = "backspace"
+	OpSettle        Op = "settle"
+	OpClearFeedback Op = "clear_feedback"
+	OpPause         Op = "pause"
+	OpResume        Op = "resume"
+	OpEnd           Op = "end"
+	OpField         Op = "field"
+)
+
+// Input is one call made to the engine, At after the run started
+type Input struct {
+	At     time.Duration `json:"at"`
+	Op     Op            `json:"op"`
+	Dt     time.Duration `json:"dt,omitempty"`
+	Text   string        `json:"text,omitempty"`
+	Index  int           `json:"index,omitempty"`
+	Width  int           `json:"width,omitempty"`
+	Height int           `json:"height,omitempty"`
+}
+
+// Recording holds everything needed to play a run again: the seed, what
+// the kana pool was made of and every call made to the engine
+type Recording struct {
+	Seed     int64                `json:"seed"`
+	Started  time.Time            `json:"started"`
+	Settings model.Settings       `json:"settings"`
+	Deck     *model.Deck          `json:"deck,omitempty"`
+	Practice []model.Kana         `json:"practice,omitempty"`
+	Daily    string               `json:"daily,omitempty"`
+	Cards    map[string]*srs.Card `json:"cards"`
+	Width    int                  `json:"width"`
+	Height   int                  `json:"height"`
+	Inputs   []Input              `json:"inputs"`
+}
+
+// Duration returns the time between the start of the run and its last input
+func (r *Recording) Duration() time.Duration {
+	if len(r.Inputs) == 0 {
+		return 0
+	}
+	return r.Inputs[len(r.Inputs)-1].At
+}
+
+// startRecording begins the recording of a new run
+func (e *Engine) startRecording(now time.Time) {
+	m := e.M
+	r := &Recording{
+		Seed:     m.Seed,
+		Started:  now,
+		Settings: m.Settings(),
+		Practice: m.PracticeKana,
+		Daily:    m.Daily,
+		Width:    m.PlayAreaWidth,
+		Height:   m.MaxFallHeight,
+	}
+	if m.SelectedKana == model.KanaTypeDeck && m.SelectedDeck >= 0 && m.SelectedDeck < len(m.Decks) {
+		deck := m.Decks[m.SelectedDeck]
+		r.Deck = &deck
+	}
+	if m.SpawnMode == model.SpawnAdaptive && m.Scheduler != nil {
+		// Adaptive spawning depends on the spaced-repetition state the run started with
+		r.Cards = make(map[string]*srs.Card, len(m.Scheduler.Cards))
+		for character, c := range m.Scheduler.Cards {
+			card := *c
+			r.Cards[character] = &card
+		}
+	}
+	e.Recording = r
+}
+
+// record appends a call to the recording of the current run, calls made
+// outside of a run do not belong to it
+func (e *Engine) record(in Input) {
+	if e.Recording == nil || (e.M.State != model.StatePlaying && e.M.State != model.StatePaused) {
+		return
+	}
+	in.At = e.M.Now.Sub(e.Recording.Started)
+	e.Recording.Inputs = append(e.Recording.Inputs, in)
+}
@@ -0,0 +1,155 @@
+package engine
+
+import "gokana/internal/model"
+
+// SpawnKana drops a new kana at the top of the play area. Every random draw
+// comes from the model's Rand, so runs started with the same seed spawn the
+// same kana. It returns false when the top row has no room for the kana, the
+// spawn is then left to a later step.
+func (e *Engine) SpawnKana() (model.FallingKana, bool) {
+	m := e.M
+	pool := m.KanaPool()
+	if m.Mode == model.ModeDrill {
+		pool = drillPool(m, pool)
+	}
+	kana := e.pickKana(pool)
+
+	pos, ok := spawnPosition(m, m.LabelWidth(kana))
+	return model.FallingKana{
+		Kana:           kana,
+		FallPosition:   0,
+		HorizontalPos:  pos,
+		ShowingCorrect: false,
+		SpawnedAt:      e.now(),
+	}, ok
+}
+
+// recycleKana sends a kana back to the top at a fresh position, it returns
+// false when the top row has no room for it
+func (e *Engine) recycleKana(fk model.FallingKana) (model.FallingKana, bool) {
+	pos, ok := spawnPosition(e.M, e.M.LabelWidth(fk.Kana))
+	fk.FallPosition = 0
+	fk.HorizontalPos = pos
+	fk.SpawnedAt = e.now()
+	return fk, ok
+}
+
+// spawnRows is how many rows below the top are kept clear of new kana when
+// possible, so they don't spawn right on top of one that just left the top
+const spawnRows = 2
+
+// spawnPosition picks a random horizontal cell where a kana of the given
+// display width neither overlaps nor touches a kana near the top rows. On a
+// crowded field touching is allowed, then sharing the second row. Kana all
+// fall at the same speed, so a kana placed apart from those of the top row
+// never overlaps another. It returns false when the top row itself is full.
+func spawnPosition(m *model.Model, width int) (int, bool) {
+	if m.PlayAreaWidth < width {
+		return 0, len(m.FallingKanas) == 0
+	}
+	for _, try := range []struct{ gap, rows int }{{1, spawnRows}, {0, spawnRows}, {0, 1}} {
+		if free := freePositions(m, width, try.gap, try.rows); len(free) > 0 {
+			return free[randIndex(m, len(free))], true
+		}
+	}
+	return 0, false
+}
+
+// freePositions lists the cells where a kana of the given width fits while
+// keeping gap empty cells away from every kana in the top rows
+func freePositions(m *model.Model, width int, gap int, rows int) []int {
+	blocked := make([]bool, m.PlayAreaWidth)
+	for _, fk := range m.FallingKanas {
+		if fk.FallPosition >= rows {
+			continue
+		}
+		for cell := fk.HorizontalPos - gap; cell < fk.HorizontalPos+m.LabelWidth(fk.Kana)+gap; cell++ {
+			if cell >= 0 && cell < len(blocked) {
+				blocked[cell] = true
+			}
+		}
+	}
+
+	var free []int
+	for pos := 0; pos+width <= m.PlayAreaWidth; pos++ {
+		fits := true
+		for cell := pos; cell < pos+width; cell++ {
+			if blocked[cell] {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			free = append(free, pos)
+		}
+	}
+	return free
+}
+
+// drillPool narrows the pool for the confusion drill: the look-alikes of a
+// kana already falling come first, so groups drop together, otherwise any
+// kana that has a look-alike in the pool starts a new group
+func drillPool(m *model.Model, pool []model.Kana) []model.Kana {
+	falling := make(map[string]bool)
+	for _, fk := range m.FallingKanas {
+		falling[fk.Kana.Character] = true
+	}
+
+	var partners []model.Kana
+	for _, fk := range m.FallingKanas {
+		if fk.ShowingCorrect {
+			continue
+		}
+		for _, k := range model.ConfusablesIn(fk.Kana.Character, pool) {
+			if !falling[k.Character] {
+				partners = append(partners, k)
+			}
+		}
+	}
+	if len(partners) > 0 {
+		return partners
+	}
+
+	var leaders []model.Kana
+	for _, k := range pool {
+		if !falling[k.Character] && len(model.ConfusablesIn(k.Character, pool)) > 0 {
+			leaders = append(leaders, k)
+		}
+	}
+	if len(leaders) > 0 {
+		return leaders
+	}
+	return pool
+}
+
+// randIndex draws an index below n. It always consumes a single value from
+// m.Rand whatever n is, so the terminal width changing the number of free
+// positions does not shift the kana drawn next.
+func randIndex(m *model.Model, n int) int {
+	return int(m.Rand.Float64() * float64(n))
+}
+
+// pickKana draws a kana from the pool, uniformly or weighted by the SRS scheduler
+func (e *Engine) pickKana(pool []model.Kana) model.Kana {
+	m := e.M
+	if m.SpawnMode != model.SpawnAdaptive || m.Scheduler == nil {
+		return pool[randIndex(m, len(pool))]
+	}
+
+	now := e.now()
+	weights := make([]float64, len(pool))
+	total := 0.0
+	for i, k := range pool {
+		weights[i] = m.Scheduler.Weight(k.Character, now)
+		total += weights[i]
+	}
+
+	target := m.Rand.Float64() * total
+	for i, w := range weights {
+		target -= w
+		if target < 0 {
+			return pool[i]
+		}
+	}
+	return pool[len(pool)-1]
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"time"
+
+	"gokana/internal/engine"
+	"gokana/internal/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DailySeed returns the seed of the daily challenge for the UTC day of t
+func DailySeed(t time.Time) int64 {
+	t = t.UTC()
+	return int64(t.Year()*10000 + int(t.Month())*100 + t.Day())
+}
+
+// startDaily switches to the fixed daily settings, keeping the menu settings
+// to restore afterwards, and starts today's run
+func startDaily(e *engine.Engine) tea.Cmd {
+	m := e.M
+	if m.SavedSettings == nil {
+		saved := m.Settings()
+		m.SavedSettings = &saved
+	}
+	m.ApplySettings(model.DailySettings())
+	m.PracticeKana = nil
+	now := time.Now()
+	m.Daily = model.DailyDate(now)
+	return StartGame(e, DailySeed(now))
+}
+
+// NewSeed returns a seed for a regular run
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// restartGame plays the same kind of run again: today's daily challenge
+// after a daily run, a freshly seeded run otherwise
+func restartGame(e *engine.Engine) tea.Cmd {
+	if e.M.Daily != "" && len(e.M.PracticeKana) == 0 {
+		return startDaily(e)
+	}
+	return StartGame(e, NewSeed())
+}
+
+// backToMenu leaves the current run, restoring the settings the daily
+// challenge replaced
+func backToMenu(m *model.Model) {
+	m.PracticeKana = nil
+	m.GameOver = false
+	m.State = model.StateMenu
+	m.Daily = ""
+	if m.SavedSettings != nil {
+		m.ApplySettings(*m.SavedSettings)
+		m.SavedSettings = nil
+	}
+}
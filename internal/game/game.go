@@ -4,29 +4,19 @@ import (
 	"math/rand"
 	"time"
 
+	"gokana/internal/deck"
+	"gokana/internal/engine"
 	"gokana/internal/model"
-)
+	"gokana/internal/scores"
 
-func SpawnKana(m *model.Model) model.FallingKana {
-	kanaSet := model.GetKanaSet(m.SelectedKana, m.DakutenEnabled)
-	return model.FallingKana{
-		Kana:           kanaSet[rand.Intn(len(kanaSet))],
-		FallPosition:   0,
-		HorizontalPos:  rand.Intn(m.PlayAreaWidth),
-		ShowingCorrect: false,
-	}
-}
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 func InitialModel() *model.Model {
-	playWidth := 55
-	return &model.Model{
+	playWidth := 60
+	m := &model.Model{
 		State:           model.StateMenu,
-		SelectedKana:    model.KanaTypeBoth,
-		DakutenEnabled:  true,
-		MenuCursor:      2,
 		MenuSection:     model.MenuSectionStart,
-		StartLevel:      1,
-		StartLives:      4,
 		FallingKanas:    []model.FallingKana{},
 		Correct:         0,
 		MaxFallHeight:   15,
@@ -34,45 +24,71 @@ func InitialModel() *model.Model {
 		FallSpeed:       time.Millisecond * 700,
 		TimeAccumulated: 0,
 		Lives:           4,
+		Rand:            rand.New(rand.NewSource(NewSeed())),
 	}
+	loadDecks(m)
+	loadSettings(m)
+	loadStats(m)
+	loadScheduler(m)
+	loadScores(m)
+	return m
 }
 
-func StartGame(m *model.Model) {
-	startLevel := m.StartLevel
-	if startLevel < 1 {
-		startLevel = 1
+// loadDecks loads the custom decks from the user config directory
+func loadDecks(m *model.Model) {
+	dir, err := deck.Dir()
+	if err != nil {
+		m.DeckErrors = append(m.DeckErrors, err.Error())
+		return
 	}
-	if startLevel > 10 {
-		startLevel = 10
+	decks, errs := deck.LoadAll(dir)
+	m.Decks = decks
+	for _, err := range errs {
+		m.DeckErrors = append(m.DeckErrors, err.Error())
 	}
+}
 
-	// Calculate speed based on level
-	speed := time.Millisecond * 700
-	for i := 1; i < startLevel; i++ {
-		speed = time.Duration(float64(speed) * 0.85)
-		if speed < time.Millisecond*100 {
-			speed = time.Millisecond * 100
-			break
+// openLeaderboard shows the high scores, starting with the current configuration
+func openLeaderboard(m *model.Model) {
+	current := m.ScoreConfig()
+	m.BoardConfigs = []scores.Config{current}
+	if m.Scores != nil {
+		for _, c := range m.Scores.Configs() {
+			if c != current {
+				m.BoardConfigs = append(m.BoardConfigs, c)
+			}
 		}
 	}
+	m.BoardCursor = 0
+	m.State = model.StateLeaderboard
+}
 
-	m.State = model.StatePlaying
-	m.FallSpeed = speed
-	m.Correct = 0
-	m.Total = 0
-	m.LevelOffset = startLevel - 1
-	m.Lives = m.StartLives
-	m.GameOver = false
-	m.Quitting = false
-	m.Input = ""
-	m.Feedback = ""
-	m.FeedbackType = ""
-	m.ShowingFeedback = false
-	m.TimeAccumulated = 0
-	m.FallingKanas = []model.FallingKana{}
-
-	// Spawn initial kanas based on level
-	for i := 0; i < startLevel; i++ {
-		m.FallingKanas = append(m.FallingKanas, SpawnKana(m))
+// StartGame starts a new run seeded with seed and its tick loop
+func StartGame(e *engine.Engine, seed int64) tea.Cmd {
+	if e.M.TermWidth > 0 && e.M.TermHeight > 0 {
+		// The play field depends on the mode, which may have changed since the last resize
+		resize(e, e.M.TermWidth, e.M.TermHeight)
+	}
+	e.Start(seed)
+	if e.M.TerminalTooSmall {
+		pauseGame(e)
+		return nil
 	}
+	e.M.TickGeneration++
+	return tick(e.M.TickGeneration)
+}
+
+// pauseGame freezes the run, the tick loop stops until resumeGame
+func pauseGame(e *engine.Engine) {
+	e.Pause()
+	SaveStores(e.M)
+	e.M.PauseCursor = model.PauseResume
+	e.M.TickGeneration++
+}
+
+// resumeGame continues a paused run with a new tick loop
+func resumeGame(e *engine.Engine) tea.Cmd {
+	e.Resume()
+	e.M.TickGeneration++
+	return tick(e.M.TickGeneration)
 }
@@ -0,0 +1,51 @@
+package game
+
+import (
+	"gokana/internal/engine"
+	"gokana/internal/model"
+)
+
+const (
+	// maxPlayAreaWidth and maxFallHeight keep the field playable on very large terminals,
+	// a taller field would also give more time to answer
+	maxPlayAreaWidth = 100
+	maxFallHeight    = 24
+
+	// horizontalChrome is the play area border plus a spare column,
+	// verticalChrome the title, stats line, input box and help around the play area
+	horizontalChrome = 3
+	verticalChrome   = 16
+
+	// candidateChrome is the numbered choices row under the input box in reverse mode
+	candidateChrome = 2
+)
+
+// resize recomputes the play field from the terminal size and moves the
+// falling kana proportionally. Below the minimum size a running game is paused.
+func resize(e *engine.Engine, width, height int) {
+	m := e.M
+	m.TermWidth = width
+	m.TermHeight = height
+	minWidth, minHeight := m.MinTerminalSize()
+	m.TerminalTooSmall = width < minWidth || height < minHeight
+
+	if m.Daily != "" {
+		// Positions are drawn over the field width, the daily field must not depend on the terminal
+		width, height = minWidth, minHeight
+	} else if m.TerminalTooSmall {
+		width, height = m.PlayAreaWidth+horizontalChrome, m.MaxFallHeight+fieldChrome(m)
+	}
+	e.SetField(min(width-horizontalChrome, maxPlayAreaWidth), min(height-fieldChrome(m), maxFallHeight))
+
+	if m.TerminalTooSmall && m.State == model.StatePlaying {
+		pauseGame(e)
+	}
+}
+
+// fieldChrome is the number of lines around the play field in the current mode
+func fieldChrome(m *model.Model) int {
+	if m.Mode == model.ModeReverse {
+		return verticalChrome + candidateChrome
+	}
+	return verticalChrome
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"gokana/internal/config"
+	"gokana/internal/model"
+)
+
+// loadSettings restores the menu settings of the last game. A deck that is
+// gone since falls back to the default character set.
+func loadSettings(m *model.Model) {
+	path, err := config.Path()
+	if err != nil {
+		m.SettingsError = err.Error()
+		return
+	}
+	c, err := config.Load(path)
+	if err != nil {
+		m.SettingsError = err.Error()
+	}
+	if c.SelectedKana == model.KanaTypeDeck {
+		c.SelectedKana = model.DefaultSettings().SelectedKana
+		for i, d := range m.Decks {
+			if d.Name == c.Deck {
+				c.SelectedKana = model.KanaTypeDeck
+				c.SelectedDeck = i
+				break
+			}
+		}
+	}
+	m.ApplySettings(c.Settings)
+}
+
+// saveSettings persists the menu settings so the next session starts with them
+func saveSettings(m *model.Model) {
+	path, err := config.Path()
+	if err != nil {
+		m.SettingsError = err.Error()
+		return
+	}
+	c := config.Config{Settings: m.Settings()}
+	if m.SelectedKana == model.KanaTypeDeck && m.SelectedDeck >= 0 && m.SelectedDeck < len(m.Decks) {
+		c.Deck = m.Decks[m.SelectedDeck].Name
+	}
+	if err := config.Save(path, c); err != nil {
+		m.SettingsError = err.Error()
+		return
+	}
+	m.SettingsError = ""
+}
+
+// resetSettings puts the menu back to the default settings
+func resetSettings(m *model.Model) {
+	m.ApplySettings(model.DefaultSettings())
+	saveSettings(m)
+}
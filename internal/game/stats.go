@@ -0,0 +1,173 @@
+package game
+
+import (
+	"gokana/internal/engine"
+	"gokana/internal/model"
+	"gokana/internal/replay"
+	"gokana/internal/scores"
+	"gokana/internal/srs"
+	"gokana/internal/stats"
+)
+
+// loadStats opens the persistent statistics store. If it cannot be read
+// statistics are disabled for the session rather than overwriting the file.
+func loadStats(m *model.Model) {
+	path, err := stats.Path()
+	if err != nil {
+		m.StatsError = err.Error()
+		return
+	}
+	store, err := stats.Load(path)
+	if err != nil {
+		m.StatsError = err.Error()
+		return
+	}
+	m.Stats = store
+}
+
+// loadScheduler opens the spaced-repetition scheduler, adaptive spawning
+// falls back to uniform if it cannot be read
+func loadScheduler(m *model.Model) {
+	path, err := srs.Path()
+	if err != nil {
+		m.SchedulerError = err.Error()
+		return
+	}
+	scheduler, err := srs.Load(path)
+	if err != nil {
+		m.SchedulerError = err.Error()
+		return
+	}
+	m.Scheduler = scheduler
+}
+
+// loadScores opens the high score table
+func loadScores(m *model.Model) {
+	path, err := scores.Path()
+	if err != nil {
+		m.ScoresError = err.Error()
+		return
+	}
+	board, err := scores.Load(path)
+	if err != nil {
+		m.ScoresError = err.Error()
+		return
+	}
+	m.Scores = board
+}
+
+// recordScore adds the finished run to the high score table. Practice runs
+// on missed kana and zen sessions are not ranked.
+func recordScore(m *model.Model) {
+	m.ScoreRank = 0
+	if m.Scores == nil || len(m.PracticeKana) > 0 || m.Zen {
+		return
+	}
+	m.ScoreRank = m.Scores.Add(scores.Score{
+		Config:  m.ScoreConfig(),
+		Points:  m.GetPoints(),
+		Correct: m.Correct,
+		Level:   m.GetLevel(),
+		Date:    m.EndedAt,
+	})
+	if err := m.Scores.Save(); err != nil {
+		m.ScoresError = err.Error()
+	}
+}
+
+// recordStats applies an event to the statistics store, SaveStores persists it
+func recordStats(m *model.Model, record func(s *stats.Store)) {
+	if m.Stats == nil {
+		return
+	}
+	record(m.Stats)
+	m.StoresDirty = true
+}
+
+// SaveStores writes the statistics and spaced-repetition reviews recorded
+// since the last save. Rewriting both files on every keystroke would stall
+// the game, so they are saved once an answer settles, on pause, at the end
+// of the run and on exit.
+func SaveStores(m *model.Model) {
+	if !m.StoresDirty {
+		return
+	}
+	m.StoresDirty = false
+	if m.Stats != nil {
+		if err := m.Stats.Save(); err != nil {
+			m.StatsError = err.Error()
+		}
+	}
+	if m.Scheduler != nil {
+		if err := m.Scheduler.Save(); err != nil {
+			m.SchedulerError = err.Error()
+		}
+	}
+}
+
+// record applies what the engine reports: per-kana statistics and
+// spaced-repetition reviews as they happen, saved by SaveStores, the score
+// and the replay when the run ends
+func record(e *engine.Engine, events []engine.Event) {
+	m := e.M
+	reviewed := false
+	for _, event := range events {
+		switch event := event.(type) {
+		case engine.Correct:
+			character := event.Kana.Kana.Character
+			recordStats(m, func(s *stats.Store) {
+				s.RecordCorrect(character, event.Elapsed)
+			})
+			reviewed = true
+
+		case engine.Missed:
+			character := event.Kana.Kana.Character
+			recordStats(m, func(s *stats.Store) {
+				s.RecordMiss(character)
+			})
+			reviewed = true
+
+		case engine.Wrong:
+			character := event.Kana.Kana.Character
+			if character == "" {
+				continue
+			}
+			recordStats(m, func(s *stats.Store) {
+				if event.Answered {
+					s.RecordWrongAnswer(character)
+				} else {
+					s.RecordWrongPrefix(character)
+				}
+			})
+			reviewed = true
+
+		case engine.GameOver:
+			recordScore(m)
+			saveReplay(e)
+		}
+	}
+	// The engine already applied the reviews, only persisting them is left
+	if reviewed && m.Scheduler != nil {
+		m.StoresDirty = true
+	}
+	if m.GameOver {
+		SaveStores(m)
+	}
+}
+
+// saveReplay writes the recording of the finished run to the replay directory
+func saveReplay(e *engine.Engine) {
+	m := e.M
+	m.ReplayPath = ""
+	m.ReplayError = ""
+	if e.Recording == nil {
+		return
+	}
+	path, err := replay.Save(e.Recording)
+	e.Recording = nil
+	if err != nil {
+		m.ReplayError = err.Error()
+		return
+	}
+	m.ReplayPath = path
+}
@@ -1,23 +1,28 @@
 package game
 
 import (
-	"strings"
 	"time"
 
+	"gokana/internal/engine"
 	"gokana/internal/model"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type tickMsg time.Time
+// tickMsg advances the falling kana. Ticks from an older generation are
+// dropped so pausing and resuming never runs two tick loops at once.
+type tickMsg struct {
+	time       time.Time
+	generation int
+}
 type correctDelayMsg time.Time
 type feedbackDelayMsg time.Time
 
 const refreshRate = time.Millisecond * 100
 
-func tick() tea.Cmd {
+func tick(generation int) tea.Cmd {
 	return tea.Tick(refreshRate, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+		return tickMsg{time: t, generation: generation}
 	})
 }
 
@@ -37,41 +42,87 @@ func Init() tea.Cmd {
 	return nil
 }
 
-func Update(m *model.Model, msg tea.Msg) (*model.Model, tea.Cmd) {
+// Update is the Bubble Tea adapter over the engine: it turns key presses and
+// timer messages into engine calls, and the events they return into
+// persisted statistics and timer commands
+func Update(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		resize(e, msg.Width, msg.Height)
+		return nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.TerminalTooSmall {
+		if msg.Type == tea.KeyCtrlC || msg.Type == tea.KeyEsc {
+			m.Quitting = true
+			return tea.Quit
+		}
+		return nil
+	}
+
 	switch m.State {
 	case model.StateMenu:
-		return updateMenu(m, msg)
+		return updateMenu(e, msg)
 	case model.StatePlaying:
-		return updatePlaying(m, msg)
+		return updatePlaying(e, msg)
 	case model.StateGameOver:
-		m.Quitting = true
-		return m, tea.Quit
+		return updateGameOver(e, msg)
+	case model.StatePaused:
+		return updatePaused(e, msg)
+	case model.StateLeaderboard:
+		return updateLeaderboard(e, msg)
 	default:
-		return m, nil
+		return nil
 	}
 }
 
-func updateMenu(m *model.Model, msg tea.Msg) (*model.Model, tea.Cmd) {
+func updateMenu(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	if m.PickingRows {
+		return updateRowPicker(e, msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
 			m.Quitting = true
-			return m, tea.Quit
+			return tea.Quit
 		case tea.KeyUp, tea.KeyShiftTab:
 			switch m.MenuSection {
+			case model.MenuSectionMode:
+				m.Mode--
+				if m.Mode < 0 {
+					m.Mode = model.GameModeCount - 1
+				}
 			case model.MenuSectionKana:
 				m.MenuCursor--
 				if m.MenuCursor < 0 {
-					m.MenuCursor = 2
+					m.MenuCursor = m.KanaOptionCount() - 1
 				}
+				selectKanaOption(m)
 			case model.MenuSectionDakuten:
 				m.DakutenEnabled = !m.DakutenEnabled
+			case model.MenuSectionYoon:
+				m.YoonEnabled = !m.YoonEnabled
+			case model.MenuSectionRomanization:
+				m.Romanization--
+				if m.Romanization < 0 {
+					m.Romanization = model.RomanizationSystemCount - 1
+				}
+			case model.MenuSectionSpawn:
+				m.SpawnMode = 1 - m.SpawnMode
 			case model.MenuSectionLevel:
 				m.StartLevel++
 				if m.StartLevel > 10 {
 					m.StartLevel = 1
 				}
+			case model.MenuSectionZen:
+				m.Zen = !m.Zen
+			case model.MenuSectionTimeAttack:
+				m.TimeAttack--
+				if m.TimeAttack < 0 {
+					m.TimeAttack = model.TimeAttackCount - 1
+				}
 			case model.MenuSectionLives:
 				m.StartLives++
 				if m.StartLives > 10 {
@@ -80,18 +131,40 @@ func updateMenu(m *model.Model, msg tea.Msg) (*model.Model, tea.Cmd) {
 			}
 		case tea.KeyDown, tea.KeyTab:
 			switch m.MenuSection {
+			case model.MenuSectionMode:
+				m.Mode++
+				if m.Mode >= model.GameModeCount {
+					m.Mode = 0
+				}
 			case model.MenuSectionKana:
 				m.MenuCursor++
-				if m.MenuCursor > 2 {
+				if m.MenuCursor >= m.KanaOptionCount() {
 					m.MenuCursor = 0
 				}
+				selectKanaOption(m)
 			case model.MenuSectionDakuten:
 				m.DakutenEnabled = !m.DakutenEnabled
+			case model.MenuSectionYoon:
+				m.YoonEnabled = !m.YoonEnabled
+			case model.MenuSectionRomanization:
+				m.Romanization++
+				if m.Romanization >= model.RomanizationSystemCount {
+					m.Romanization = 0
+				}
+			case model.MenuSectionSpawn:
+				m.SpawnMode = 1 - m.SpawnMode
 			case model.MenuSectionLevel:
 				m.StartLevel--
 				if m.StartLevel < 1 {
 					m.StartLevel = 10
 				}
+			case model.MenuSectionZen:
+				m.Zen = !m.Zen
+			case model.MenuSectionTimeAttack:
+				m.TimeAttack++
+				if m.TimeAttack >= model.TimeAttackCount {
+					m.TimeAttack = 0
+				}
 			case model.MenuSectionLives:
 				m.StartLives--
 				if m.StartLives < 1 {
@@ -100,187 +173,290 @@ func updateMenu(m *model.Model, msg tea.Msg) (*model.Model, tea.Cmd) {
 			}
 		case tea.KeyLeft:
 			m.MenuSection--
-			if m.MenuSection < model.MenuSectionKana {
-				m.MenuSection = model.MenuSectionStart
+			if m.MenuSection < model.MenuSectionMode {
+				m.MenuSection = model.MenuSectionScores
 			}
 		case tea.KeyRight:
 			m.MenuSection++
-			if m.MenuSection > model.MenuSectionStart {
-				m.MenuSection = model.MenuSectionKana
+			if m.MenuSection > model.MenuSectionScores {
+				m.MenuSection = model.MenuSectionMode
 			}
 		case tea.KeyEnter, tea.KeySpace:
-			if m.MenuSection == model.MenuSectionKana {
-				m.SelectedKana = model.KanaType(m.MenuCursor)
-				m.MenuSection = model.MenuSectionDakuten
+			if m.MenuSection == model.MenuSectionRows {
+				m.PickingRows = true
+				m.RowCursor = 0
 			} else if m.MenuSection == model.MenuSectionStart {
-				StartGame(m)
-				return m, tick()
+				saveSettings(m)
+				return StartGame(e, NewSeed())
+			} else if m.MenuSection == model.MenuSectionDaily {
+				saveSettings(m)
+				return startDaily(e)
+			} else if m.MenuSection == model.MenuSectionScores {
+				openLeaderboard(m)
 			} else {
 				m.MenuSection++
 			}
+		case tea.KeyRunes:
+			if string(msg.Runes) == "r" {
+				resetSettings(m)
+			}
 		}
 	}
-	return m, nil
+	return nil
+}
+
+// selectKanaOption selects the character set under the menu cursor
+func selectKanaOption(m *model.Model) {
+	if m.MenuCursor < model.BuiltinKanaOptions {
+		m.SelectedKana = model.KanaType(m.MenuCursor)
+	} else {
+		m.SelectedKana = model.KanaTypeDeck
+		m.SelectedDeck = m.MenuCursor - model.BuiltinKanaOptions
+	}
 }
 
-func updatePlaying(m *model.Model, msg tea.Msg) (*model.Model, tea.Cmd) {
+func updateRowPicker(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
 	switch msg := msg.(type) {
-	case correctDelayMsg:
-		newFalling := []model.FallingKana{}
-		for _, fk := range m.FallingKanas {
-			if !fk.ShowingCorrect {
-				newFalling = append(newFalling, fk)
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			m.Quitting = true
+			return tea.Quit
+		case tea.KeyUp, tea.KeyShiftTab:
+			m.RowCursor--
+			if m.RowCursor < 0 {
+				m.RowCursor = model.KanaRowCount - 1
 			}
+		case tea.KeyDown, tea.KeyTab:
+			m.RowCursor++
+			if m.RowCursor >= model.KanaRowCount {
+				m.RowCursor = 0
+			}
+		case tea.KeySpace:
+			row := model.KanaRow(m.RowCursor)
+			m.SelectedRows[row] = !m.SelectedRows[row]
+		case tea.KeyRunes:
+			switch string(msg.Runes) {
+			case "a":
+				m.SelectedRows = model.AllRows()
+			case "n":
+				m.SelectedRows = map[model.KanaRow]bool{}
+			}
+		case tea.KeyEnter, tea.KeyEsc:
+			hasRow := false
+			for _, selected := range m.SelectedRows {
+				if selected {
+					hasRow = true
+					break
+				}
+			}
+			if !hasRow {
+				m.SelectedRows = model.AllRows()
+			}
+			m.PickingRows = false
+			m.MenuSection = model.MenuSectionRows
 		}
-		m.FallingKanas = newFalling
-
-		level := m.GetLevel()
-		for len(m.FallingKanas) < level {
-			m.FallingKanas = append(m.FallingKanas, SpawnKana(m))
-		}
+	}
+	return nil
+}
 
-		m.Input = ""
-		m.TimeAccumulated = 0
-		return m, nil
+func updatePlaying(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	switch msg := msg.(type) {
+	case correctDelayMsg:
+		cmd := handle(e, e.Settle())
+		SaveStores(m)
+		return cmd
 
 	case feedbackDelayMsg:
-		m.ShowingFeedback = false
-		m.Feedback = ""
-		m.FeedbackType = ""
-		return m, nil
+		e.ClearFeedback()
+		SaveStores(m)
+		return nil
 
 	case tickMsg:
-		if m.Quitting || m.GameOver {
-			return m, nil
+		if m.Quitting || m.GameOver || msg.generation != m.TickGeneration {
+			return nil
+		}
+		cmd := handle(e, e.Step(refreshRate))
+		if m.GameOver || (m.Mode == model.ModeQuiz && !m.Timed()) {
+			// The quiz waits for the player, only a time attack needs the clock
+			return cmd
 		}
+		return tea.Batch(tick(m.TickGeneration), cmd)
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc:
+			if m.Zen || m.Mode == model.ModeQuiz {
+				// These runs never end on their own, leaving them shows the results
+				return handle(e, e.End())
+			}
+			m.Quitting = true
+			return tea.Quit
 
-		m.TimeAccumulated += refreshRate
-		if m.TimeAccumulated >= m.FallSpeed {
-			m.TimeAccumulated -= m.FallSpeed
+		case tea.KeyCtrlC:
+			m.Quitting = true
+			return tea.Quit
 
-			newFalling := []model.FallingKana{}
-			var cmd tea.Cmd = nil
+		case tea.KeyTab, tea.KeyCtrlP:
+			pauseGame(e)
+			return nil
 
-			for _, fk := range m.FallingKanas {
-				if fk.ShowingCorrect {
-					newFalling = append(newFalling, fk)
-					continue
-				}
+		case tea.KeyBackspace:
+			return handle(e, e.Backspace())
 
-				fk.FallPosition++
-				if fk.FallPosition >= m.MaxFallHeight {
-					m.Lives--
-					m.Total++
-					if m.Lives <= 0 {
-						m.GameOver = true
-						m.State = model.StateGameOver
-						m.Quitting = true
-						return m, tea.Quit
-					} else {
-						m.FeedbackType = "wrong"
-						m.ShowingFeedback = true
-						if cmd == nil {
-							cmd = feedbackDelay()
-						}
-					}
-					m.Input = ""
-					newFalling = append(newFalling, SpawnKana(m))
-				} else {
-					newFalling = append(newFalling, fk)
-				}
+		case tea.KeyRunes:
+			if isChoiceKey(m, msg.Runes) {
+				return handle(e, e.Choose(int(msg.Runes[0]-'1')))
 			}
+			return handle(e, e.Submit(string(msg.Runes)))
+		}
+	}
+	return nil
+}
 
-			m.FallingKanas = newFalling
-			if cmd != nil {
-				return m, tea.Batch(tick(), cmd)
+// isChoiceKey checks if the key picks a numbered quiz option or reverse mode candidate
+func isChoiceKey(m *model.Model, runes []rune) bool {
+	if m.Mode != model.ModeQuiz && m.Mode != model.ModeReverse {
+		return false
+	}
+	return len(runes) == 1 && runes[0] >= '1' && runes[0] <= '9'
+}
+
+// handle records the engine events and schedules the animations they start
+func handle(e *engine.Engine, events []engine.Event) tea.Cmd {
+	m := e.M
+	record(e, events)
+
+	var cmds []tea.Cmd
+	feedback := false
+	for _, event := range events {
+		switch event := event.(type) {
+		case engine.Correct:
+			cmds = append(cmds, correctDelay())
+		case engine.Missed:
+			feedback = true
+		case engine.Wrong:
+			feedback = true
+			if event.Answered {
+				// Leave the right answer on screen a little longer after a mistake
+				cmds = append(cmds, tea.Tick(time.Second, func(t time.Time) tea.Msg {
+					return correctDelayMsg(t)
+				}))
 			}
+		case engine.GameOver:
+			m.GameOverCursor = model.GameOverReplay
+			return nil
 		}
-		return m, tick()
+	}
+	if feedback {
+		cmds = append(cmds, feedbackDelay())
+	}
+	return tea.Batch(cmds...)
+}
 
+func updateGameOver(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
 			m.Quitting = true
-			return m, tea.Quit
-
-		case tea.KeyBackspace:
-			if m.GameOver {
-				return m, nil
+			return tea.Quit
+		case tea.KeyUp, tea.KeyShiftTab:
+			m.GameOverCursor--
+			if m.GameOverCursor < 0 {
+				m.GameOverCursor = model.GameOverOptionCount - 1
 			}
-			if m.ShowingFeedback {
-				return m, nil
+			if m.GameOverCursor == model.GameOverPractice && len(m.Mistakes) == 0 {
+				m.GameOverCursor--
 			}
-			hasShowingCorrect := m.HasShowingCorrect()
-			if hasShowingCorrect {
-				m.Input = ""
-				for i := range m.FallingKanas {
-					m.FallingKanas[i].ShowingCorrect = false
-				}
-				return m, nil
+		case tea.KeyDown, tea.KeyTab:
+			m.GameOverCursor++
+			if m.GameOverCursor == model.GameOverPractice && len(m.Mistakes) == 0 {
+				m.GameOverCursor++
 			}
-			if len(m.Input) > 0 {
-				m.Input = m.Input[:len(m.Input)-1]
-				m.Feedback = ""
+			if m.GameOverCursor >= model.GameOverOptionCount {
+				m.GameOverCursor = 0
 			}
-
-		case tea.KeyRunes:
-			if m.GameOver {
-				return m, nil
+		case tea.KeyEnter, tea.KeySpace:
+			switch m.GameOverCursor {
+			case model.GameOverReplay:
+				return restartGame(e)
+			case model.GameOverPractice:
+				m.PracticeKana = m.MistakeKana()
+				return StartGame(e, NewSeed())
+			case model.GameOverMenu:
+				backToMenu(m)
+			case model.GameOverQuit:
+				m.Quitting = true
+				return tea.Quit
 			}
-			if m.ShowingFeedback {
-				return m, nil
+		}
+	}
+	return nil
+}
+
+func updateLeaderboard(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			m.Quitting = true
+			return tea.Quit
+		case tea.KeyLeft, tea.KeyUp, tea.KeyShiftTab:
+			m.BoardCursor--
+			if m.BoardCursor < 0 {
+				m.BoardCursor = len(m.BoardConfigs) - 1
 			}
-			hasShowingCorrect := m.HasShowingCorrect()
-			if hasShowingCorrect {
-				m.Input = ""
-				for i := range m.FallingKanas {
-					m.FallingKanas[i].ShowingCorrect = false
-				}
+		case tea.KeyRight, tea.KeyDown, tea.KeyTab:
+			m.BoardCursor++
+			if m.BoardCursor >= len(m.BoardConfigs) {
+				m.BoardCursor = 0
 			}
+		case tea.KeyEnter, tea.KeyEsc:
+			m.State = model.StateMenu
+		}
+	}
+	return nil
+}
 
-			m.Input += string(msg.Runes)
-			answer := strings.TrimSpace(strings.ToLower(m.Input))
+func updatePaused(e *engine.Engine, msg tea.Msg) tea.Cmd {
+	m := e.M
+	switch msg := msg.(type) {
+	case correctDelayMsg, feedbackDelayMsg:
+		// Let pending animations finish so nothing is stuck after resuming
+		return updatePlaying(e, msg)
 
-			matchedIndex := -1
-			isValidPrefix := false
-			for i, fk := range m.FallingKanas {
-				if answer == fk.Kana.Romaji {
-					matchedIndex = i
-					isValidPrefix = true
-					break
-				}
-				if strings.HasPrefix(fk.Kana.Romaji, answer) {
-					isValidPrefix = true
-				}
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			m.Quitting = true
+			return tea.Quit
+		case tea.KeyTab, tea.KeyCtrlP, tea.KeyEsc:
+			return resumeGame(e)
+		case tea.KeyUp, tea.KeyShiftTab:
+			m.PauseCursor--
+			if m.PauseCursor < 0 {
+				m.PauseCursor = model.PauseOptionCount - 1
 			}
-
-			if matchedIndex != -1 {
-				m.Total++
-				m.Correct++
-				m.FeedbackType = "correct"
-				m.FallingKanas[matchedIndex].ShowingCorrect = true
-				m.TimeAccumulated = 0
-
-				if m.Correct%20 == 0 {
-					m.FallSpeed = time.Duration(float64(m.FallSpeed) * 0.85)
-					if m.FallSpeed < time.Millisecond*100 {
-						m.FallSpeed = time.Millisecond * 100
-					}
-					level := m.GetLevel()
-					for len(m.FallingKanas) < level {
-						m.FallingKanas = append(m.FallingKanas, SpawnKana(m))
-					}
-				}
-				return m, correctDelay()
-			} else if !isValidPrefix {
-				m.FeedbackType = "wrong"
-				m.ShowingFeedback = true
-				m.Input = ""
-				return m, feedbackDelay()
-			} else {
-				m.FeedbackType = ""
+		case tea.KeyDown:
+			m.PauseCursor++
+			if m.PauseCursor >= model.PauseOptionCount {
+				m.PauseCursor = 0
+			}
+		case tea.KeyEnter, tea.KeySpace:
+			switch m.PauseCursor {
+			case model.PauseResume:
+				return resumeGame(e)
+			case model.PauseRestart:
+				return restartGame(e)
+			case model.PauseMenu:
+				backToMenu(m)
 			}
 		}
 	}
-	return m, nil
+	return nil
 }
@@ -0,0 +1,244 @@
+package ime
+
+import "strings"
+
+// syllables maps romaji to hiragana, the composer always picks the longest match
+var syllables = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"sa": "さ", "shi": "し", "si": "し", "su": "す", "se": "せ", "so": "そ",
+	"ta": "た", "chi": "ち", "ti": "ち", "tsu": "つ", "tu": "つ", "te": "て", "to": "と",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"ha": "は", "hi": "ひ", "fu": "ふ", "hu": "ふ", "he": "へ", "ho": "ほ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"wa": "わ", "wo": "を",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"za": "ざ", "ji": "じ", "zi": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"vu": "ゔ",
+
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sha": "しゃ", "shu": "しゅ", "sho": "しょ", "sya": "しゃ", "syu": "しゅ", "syo": "しょ",
+	"cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ", "tya": "ちゃ", "tyu": "ちゅ", "tyo": "ちょ",
+	"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"ja": "じゃ", "ju": "じゅ", "jo": "じょ", "zya": "じゃ", "zyu": "じゅ", "zyo": "じょ",
+	"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+	"dya": "ぢゃ", "dyu": "ぢゅ", "dyo": "ぢょ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+
+	"fa": "ふぁ", "fi": "ふぃ", "fe": "ふぇ", "fo": "ふぉ",
+	"she": "しぇ", "che": "ちぇ", "je": "じぇ",
+
+	"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+	"la": "ぁ", "li": "ぃ", "lu": "ぅ", "le": "ぇ", "lo": "ぉ",
+	"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ", "lya": "ゃ", "lyu": "ゅ", "lyo": "ょ",
+	"xtu": "っ", "ltu": "っ", "xtsu": "っ", "ltsu": "っ",
+	"-": "ー",
+}
+
+// maxSyllableLen is the length of the longest romaji key in syllables
+const maxSyllableLen = 4
+
+// Compose converts romaji to hiragana the way an IME does while typing.
+// Double consonants become っ, n becomes ん before a consonant, after n'
+// or as nn, and contracted sounds (kya, sho, ja...) become yōon. The
+// trailing romaji that cannot be converted yet is returned as pending.
+// Characters that can never be converted are kept as they are.
+func Compose(romaji string) (kana string, pending string) {
+	var out strings.Builder
+	s := strings.ToLower(romaji)
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		if c == 'n' {
+			if i+1 == len(s) {
+				return out.String(), "n"
+			}
+			next := s[i+1]
+			switch {
+			case next == 'n' || next == '\'':
+				out.WriteString("ん")
+				i += 2
+				continue
+			case !isVowel(next) && next != 'y':
+				out.WriteString("ん")
+				i++
+				continue
+			}
+		}
+
+		// Sokuon: a doubled consonant, or "tch" as in matcha
+		if isConsonant(c) && i+1 < len(s) && (s[i+1] == c || (c == 't' && strings.HasPrefix(s[i+1:], "ch"))) {
+			out.WriteString("っ")
+			i++
+			continue
+		}
+
+		matched := false
+		for l := min(maxSyllableLen, len(s)-i); l >= 1; l-- {
+			if k, ok := syllables[s[i:i+l]]; ok {
+				out.WriteString(k)
+				i += l
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if isSyllablePrefix(s[i:]) {
+			return out.String(), s[i:]
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String(), ""
+}
+
+// Matches checks typed romaji against a kana target, ignoring the script
+// so hiragana input matches katakana, and spelling long vowels either way
+// (コーヒー matches both koohii and ko-hi-). complete is true when the
+// input composes exactly to the target, prefix when it can still lead to it.
+func Matches(input, target string) (complete bool, prefix bool) {
+	kana, pending := Compose(input)
+	kana = Normalize(kana)
+	target = Normalize(target)
+	if !strings.HasPrefix(target, kana) {
+		return false, false
+	}
+	rest := target[len(kana):]
+
+	switch {
+	case pending == "":
+		return rest == "", true
+	case pending == "n" && rest == "ん":
+		// A final n is enough for ん, as long as nothing else is expected
+		return true, true
+	}
+	return false, canComplete(pending, rest)
+}
+
+// canComplete checks if pending romaji can still be typed into the start of rest
+func canComplete(pending, rest string) bool {
+	if rest == "" {
+		return false
+	}
+	if pending == "n" && strings.HasPrefix(rest, "ん") {
+		return true
+	}
+	if len(pending) == 1 && isConsonant(pending[0]) && strings.HasPrefix(rest, "っ") {
+		return true
+	}
+	for romaji, k := range syllables {
+		if strings.HasPrefix(romaji, pending) && (strings.HasPrefix(rest, k) || strings.HasPrefix(k, rest)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize converts katakana to hiragana and spells each long vowel mark
+// as the vowel it lengthens, so コーヒー and こおひい compare equal
+func Normalize(s string) string {
+	var out strings.Builder
+	var previous rune
+	for _, r := range ToHiragana(s) {
+		if r == 'ー' {
+			if vowel, ok := vowels[previous]; ok {
+				out.WriteRune(vowel)
+				previous = vowel
+				continue
+			}
+		}
+		out.WriteRune(r)
+		previous = r
+	}
+	return out.String()
+}
+
+// vowels maps each hiragana to the vowel it ends with, built from syllables
+var vowels = func() map[rune]rune {
+	vowelKana := map[byte]rune{'a': 'あ', 'i': 'い', 'u': 'う', 'e': 'え', 'o': 'お'}
+	v := make(map[rune]rune)
+	for romaji, kana := range syllables {
+		last := []rune(kana)
+		vowel, ok := vowelKana[romaji[len(romaji)-1]]
+		if ok {
+			v[last[len(last)-1]] = vowel
+		}
+	}
+	return v
+}()
+
+// ToHiragana converts katakana to hiragana, leaving other characters as they are
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
+
+// ToKatakana converts hiragana to katakana, leaving other characters as they are
+func ToKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + 0x60
+		}
+		return r
+	}, s)
+}
+
+// IsKatakana checks if the string contains katakana
+func IsKatakana(s string) bool {
+	for _, r := range s {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKana checks if the string is made only of kana and long vowel marks,
+// the text composition can produce
+func IsKana(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'ぁ' || r > 'ゖ') && (r < 'ァ' || r > 'ヶ') && r != 'ー' {
+			return false
+		}
+	}
+	return true
+}
+
+func isSyllablePrefix(s string) bool {
+	for romaji := range syllables {
+		if strings.HasPrefix(romaji, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c) && c != 'n'
+}
@@ -0,0 +1,80 @@
+package ime
+
+import "testing"
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		romaji, kana, pending string
+	}{
+		{"ka", "か", ""},
+		{"shi", "し", ""},
+		{"si", "し", ""},
+		{"kya", "きゃ", ""},
+		{"ja", "じゃ", ""},
+		{"kka", "っか", ""},
+		{"matcha", "まっちゃ", ""},
+		{"kanji", "かんじ", ""},
+		{"konnnichiha", "こんにちは", ""},
+		{"kan'i", "かんい", ""},
+		{"ko-hi-", "こーひー", ""},
+		{"KA", "か", ""},
+		{"n", "", "n"},
+		{"ky", "", "ky"},
+		{"kak", "か", "k"},
+		{"ka1", "か1", ""},
+	}
+	for _, tt := range tests {
+		kana, pending := Compose(tt.romaji)
+		if kana != tt.kana || pending != tt.pending {
+			t.Errorf("Compose(%q) = %q, %q, want %q, %q", tt.romaji, kana, pending, tt.kana, tt.pending)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		input, target    string
+		complete, prefix bool
+	}{
+		{"ka", "か", true, true},
+		{"ka", "カ", true, true},
+		{"k", "か", false, true},
+		{"ki", "か", false, false},
+		{"hon", "ほん", true, true},
+		{"honn", "ほん", true, true},
+		{"ho", "ほん", false, true},
+		{"ky", "きゃ", false, true},
+		{"kk", "っか", false, true},
+		{"koohii", "コーヒー", true, true},
+		{"ko-hi-", "コーヒー", true, true},
+		{"ko", "コーヒー", false, true},
+		{"ka", "コーヒー", false, false},
+		{"kon", "こんにちは", false, true},
+		{"", "か", false, true},
+	}
+	for _, tt := range tests {
+		complete, prefix := Matches(tt.input, tt.target)
+		if complete != tt.complete || prefix != tt.prefix {
+			t.Errorf("Matches(%q, %q) = %v, %v, want %v, %v", tt.input, tt.target, complete, prefix, tt.complete, tt.prefix)
+		}
+	}
+}
+
+func TestIsKana(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"か", true},
+		{"コーヒー", true},
+		{"きゃ", true},
+		{"日本", false},
+		{"かa", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsKana(tt.s); got != tt.want {
+			t.Errorf("IsKana(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
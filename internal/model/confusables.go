@@ -0,0 +1,112 @@
+package model
+
+import "slices"
+
+// lookAlikeGroups lists kana that are easily mistaken for each other by shape
+var lookAlikeGroups = [][]string{
+	// Hiragana
+	{"あ", "お", "め"},
+	{"い", "り", "こ"},
+	{"う", "つ", "ら"},
+	{"き", "さ", "ち"},
+	{"く", "へ"},
+	{"け", "は", "ほ"},
+	{"ぬ", "め"},
+	{"ね", "れ", "わ"},
+	{"る", "ろ"},
+	{"ま", "も"},
+	{"ゆ", "よ"},
+	{"た", "な"},
+	{"そ", "る"},
+	{"ば", "ぱ"},
+	{"び", "ぴ"},
+	{"ぶ", "ぷ"},
+	{"べ", "ぺ"},
+	{"ぼ", "ぽ"},
+	{"しゃ", "しょ"},
+
+	// Katakana
+	{"シ", "ツ"},
+	{"ソ", "ン"},
+	{"ソ", "リ"},
+	{"ク", "ケ", "タ"},
+	{"ウ", "ワ", "フ"},
+	{"コ", "ユ", "ロ"},
+	{"ノ", "メ", "ソ"},
+	{"チ", "テ"},
+	{"ア", "マ"},
+	{"ス", "ヌ"},
+	{"ナ", "メ"},
+	{"ヲ", "ヨ"},
+	{"セ", "サ"},
+	{"ハ", "ル"},
+	{"バ", "パ"},
+	{"ビ", "ピ"},
+	{"ブ", "プ"},
+	{"ベ", "ペ"},
+	{"ボ", "ポ"},
+}
+
+// soundAlikeGroups lists kana that are pronounced (nearly) the same
+var soundAlikeGroups = [][]string{
+	{"じ", "ぢ"},
+	{"ず", "づ"},
+	{"お", "を"},
+	{"じゃ", "ぢゃ"},
+	{"じゅ", "ぢゅ"},
+	{"じょ", "ぢょ"},
+	{"ジ", "ヂ"},
+	{"ズ", "ヅ"},
+	{"オ", "ヲ"},
+	{"ジャ", "ヂャ"},
+	{"ジュ", "ヂュ"},
+	{"ジョ", "ヂョ"},
+}
+
+// Confusable holds the kana a character is commonly mixed up with
+type Confusable struct {
+	LookAlikes  []string
+	SoundAlikes []string
+}
+
+// All returns the look-alikes followed by the sound-alikes
+func (c Confusable) All() []string {
+	return append(append([]string{}, c.LookAlikes...), c.SoundAlikes...)
+}
+
+// Confusables maps each kana to the kana it is commonly mixed up with, built
+// from the groups above so the relation is always symmetric
+var Confusables = func() map[string]Confusable {
+	c := make(map[string]Confusable)
+	link := func(groups [][]string, add func(Confusable, string) Confusable) {
+		for _, group := range groups {
+			for _, a := range group {
+				for _, b := range group {
+					if a != b && !slices.Contains(c[a].All(), b) {
+						c[a] = add(c[a], b)
+					}
+				}
+			}
+		}
+	}
+	link(lookAlikeGroups, func(e Confusable, b string) Confusable {
+		e.LookAlikes = append(e.LookAlikes, b)
+		return e
+	})
+	link(soundAlikeGroups, func(e Confusable, b string) Confusable {
+		e.SoundAlikes = append(e.SoundAlikes, b)
+		return e
+	})
+	return c
+}()
+
+// ConfusablesIn returns the kana of pool that character is commonly mixed up with
+func ConfusablesIn(character string, pool []Kana) []Kana {
+	var out []Kana
+	for _, k := range pool {
+		if slices.Contains(Confusables[character].All(), k.Character) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,13 @@
+package model
+
+// Deck is a user supplied list of characters loaded from a deck file
+type Deck struct {
+	Name        string
+	Description string
+	Path        string
+	Kana        []Kana
+}
+
+// BuiltinKanaOptions is the number of built-in character sets shown in the menu
+// before the custom decks (Hiragana, Katakana, Both)
+const BuiltinKanaOptions = 3
@@ -1,11 +1,14 @@
 package model
 
+import "time"
+
 type KanaType int
 
 const (
 	KanaTypeHiragana KanaType = iota
 	KanaTypeKatakana
 	KanaTypeBoth
+	KanaTypeDeck
 )
 
 func (k KanaType) String() string {
@@ -16,117 +19,257 @@ func (k KanaType) String() string {
 		return "Katakana"
 	case KanaTypeBoth:
 		return "Both"
+	case KanaTypeDeck:
+		return "Custom Deck"
 	default:
 		return "Unknown"
 	}
 }
 
-// Kana represents a Japanese kana character and its romanization
+// KanaRow is a row of the gojūon table, yōon belong to the row of their base kana
+type KanaRow int
+
+const (
+	RowA KanaRow = iota
+	RowKa
+	RowSa
+	RowTa
+	RowNa
+	RowHa
+	RowMa
+	RowYa
+	RowRa
+	RowWa
+	RowGa
+	RowZa
+	RowDa
+	RowBa
+	RowPa
+)
+
+// KanaRowCount is the number of gojūon rows
+const KanaRowCount = 15
+
+// RowNone is the row of kana outside the gojūon table. The row filter only
+// keeps them when every row is selected.
+const RowNone KanaRow = -1
+
+var kanaRowNames = [KanaRowCount]struct {
+	hiragana string
+	katakana string
+}{
+	{"あ", "ア"}, {"か", "カ"}, {"さ", "サ"}, {"た", "タ"}, {"な", "ナ"},
+	{"は", "ハ"}, {"ま", "マ"}, {"や", "ヤ"}, {"ら", "ラ"}, {"わ", "ワ"},
+	{"が", "ガ"}, {"ざ", "ザ"}, {"だ", "ダ"}, {"ば", "バ"}, {"ぱ", "パ"},
+}
+
+func (r KanaRow) String() string {
+	if r < 0 || r >= KanaRowCount {
+		return "Unknown"
+	}
+	return kanaRowNames[r].hiragana + "-row"
+}
+
+// Label returns the hiragana and katakana heading of the row
+func (r KanaRow) Label() string {
+	if r < 0 || r >= KanaRowCount {
+		return "?"
+	}
+	return kanaRowNames[r].hiragana + " " + kanaRowNames[r].katakana
+}
+
+// AllRows returns a row selection containing every gojūon row
+func AllRows() map[KanaRow]bool {
+	rows := make(map[KanaRow]bool, KanaRowCount)
+	for r := KanaRow(0); r < KanaRowCount; r++ {
+		rows[r] = true
+	}
+	return rows
+}
+
+// IsDakuten checks if the row only exists with dakuten or handakuten
+func (r KanaRow) IsDakuten() bool {
+	return r >= RowGa
+}
+
+// Kana represents a Japanese kana character and its romanization.
+// Romaji is the canonical spelling used for display, Alternates holds
+// other accepted spellings (Kunrei/Nihon-shiki, IME style).
 type Kana struct {
-	Character string
-	Romaji    string
+	Character  string
+	Romaji     string
+	Row        KanaRow
+	Alternates []string
+}
+
+// Answers returns every accepted romanization, canonical spelling first
+func (k Kana) Answers() []string {
+	answers := make([]string, 0, 1+len(k.Alternates))
+	answers = append(answers, k.Romaji)
+	answers = append(answers, k.Alternates...)
+	return answers
 }
 
-// FallingKana represents a kana falling in the game
+// FallingKana represents a kana falling in the game.
+// HorizontalPos is the terminal cell of its left edge within the play area.
 type FallingKana struct {
 	Kana           Kana
 	FallPosition   int
 	HorizontalPos  int
 	ShowingCorrect bool
+	SpawnedAt      time.Time
 }
 
 // MainHiragana contains all the main hiragana characters
 var MainHiragana = []Kana{
-	{"あ", "a"}, {"い", "i"}, {"う", "u"}, {"え", "e"}, {"お", "o"},
-	{"か", "ka"}, {"き", "ki"}, {"く", "ku"}, {"け", "ke"}, {"こ", "ko"},
-	{"さ", "sa"}, {"し", "shi"}, {"す", "su"}, {"せ", "se"}, {"そ", "so"},
-	{"た", "ta"}, {"ち", "chi"}, {"つ", "tsu"}, {"て", "te"}, {"と", "to"},
-	{"な", "na"}, {"に", "ni"}, {"ぬ", "nu"}, {"ね", "ne"}, {"の", "no"},
-	{"は", "ha"}, {"ひ", "hi"}, {"ふ", "fu"}, {"へ", "he"}, {"ほ", "ho"},
-	{"ま", "ma"}, {"み", "mi"}, {"む", "mu"}, {"め", "me"}, {"も", "mo"},
-	{"や", "ya"}, {"ゆ", "yu"}, {"よ", "yo"},
-	{"ら", "ra"}, {"り", "ri"}, {"る", "ru"}, {"れ", "re"}, {"ろ", "ro"},
-	{"わ", "wa"}, {"を", "wo"}, {"ん", "n"},
+	{"あ", "a", RowA, nil}, {"い", "i", RowA, nil}, {"う", "u", RowA, nil}, {"え", "e", RowA, nil}, {"お", "o", RowA, nil},
+	{"か", "ka", RowKa, nil}, {"き", "ki", RowKa, nil}, {"く", "ku", RowKa, nil}, {"け", "ke", RowKa, nil}, {"こ", "ko", RowKa, nil},
+	{"さ", "sa", RowSa, nil}, {"し", "shi", RowSa, []string{"si"}}, {"す", "su", RowSa, nil}, {"せ", "se", RowSa, nil}, {"そ", "so", RowSa, nil},
+	{"た", "ta", RowTa, nil}, {"ち", "chi", RowTa, []string{"ti"}}, {"つ", "tsu", RowTa, []string{"tu"}}, {"て", "te", RowTa, nil}, {"と", "to", RowTa, nil},
+	{"な", "na", RowNa, nil}, {"に", "ni", RowNa, nil}, {"ぬ", "nu", RowNa, nil}, {"ね", "ne", RowNa, nil}, {"の", "no", RowNa, nil},
+	{"は", "ha", RowHa, nil}, {"ひ", "hi", RowHa, nil}, {"ふ", "fu", RowHa, []string{"hu"}}, {"へ", "he", RowHa, nil}, {"ほ", "ho", RowHa, nil},
+	{"ま", "ma", RowMa, nil}, {"み", "mi", RowMa, nil}, {"む", "mu", RowMa, nil}, {"め", "me", RowMa, nil}, {"も", "mo", RowMa, nil},
+	{"や", "ya", RowYa, nil}, {"ゆ", "yu", RowYa, nil}, {"よ", "yo", RowYa, nil},
+	{"ら", "ra", RowRa, nil}, {"り", "ri", RowRa, nil}, {"る", "ru", RowRa, nil}, {"れ", "re", RowRa, nil}, {"ろ", "ro", RowRa, nil},
+	{"わ", "wa", RowWa, nil}, {"を", "wo", RowWa, nil}, {"ん", "n", RowWa, []string{"nn"}},
 }
 
 // DakutenHiragana contains hiragana characters with dakuten (゛) marks
 var DakutenHiragana = []Kana{
-	{"が", "ga"}, {"ぎ", "gi"}, {"ぐ", "gu"}, {"げ", "ge"}, {"ご", "go"},
-	{"ざ", "za"}, {"じ", "ji"}, {"ず", "zu"}, {"ぜ", "ze"}, {"ぞ", "zo"},
-	{"だ", "da"}, {"ぢ", "di"}, {"づ", "du"}, {"で", "de"}, {"ど", "do"},
-	{"ば", "ba"}, {"び", "bi"}, {"ぶ", "bu"}, {"べ", "be"}, {"ぼ", "bo"},
+	{"が", "ga", RowGa, nil}, {"ぎ", "gi", RowGa, nil}, {"ぐ", "gu", RowGa, nil}, {"げ", "ge", RowGa, nil}, {"ご", "go", RowGa, nil},
+	{"ざ", "za", RowZa, nil}, {"じ", "ji", RowZa, []string{"zi"}}, {"ず", "zu", RowZa, nil}, {"ぜ", "ze", RowZa, nil}, {"ぞ", "zo", RowZa, nil},
+	{"だ", "da", RowDa, nil}, {"ぢ", "di", RowDa, []string{"ji", "zi"}}, {"づ", "du", RowDa, []string{"zu"}}, {"で", "de", RowDa, nil}, {"ど", "do", RowDa, nil},
+	{"ば", "ba", RowBa, nil}, {"び", "bi", RowBa, nil}, {"ぶ", "bu", RowBa, nil}, {"べ", "be", RowBa, nil}, {"ぼ", "bo", RowBa, nil},
 }
 
 // HandakutenHiragana contains hiragana characters with handakuten (゜) marks
 var HandakutenHiragana = []Kana{
-	{"ぱ", "pa"}, {"ぴ", "pi"}, {"ぷ", "pu"}, {"ぺ", "pe"}, {"ぽ", "po"},
+	{"ぱ", "pa", RowPa, nil}, {"ぴ", "pi", RowPa, nil}, {"ぷ", "pu", RowPa, nil}, {"ぺ", "pe", RowPa, nil}, {"ぽ", "po", RowPa, nil},
 }
 
 // MainKatakana contains all the main katakana characters
 var MainKatakana = []Kana{
-	{"ア", "a"}, {"イ", "i"}, {"ウ", "u"}, {"エ", "e"}, {"オ", "o"},
-	{"カ", "ka"}, {"キ", "ki"}, {"ク", "ku"}, {"ケ", "ke"}, {"コ", "ko"},
-	{"サ", "sa"}, {"シ", "shi"}, {"ス", "su"}, {"セ", "se"}, {"ソ", "so"},
-	{"タ", "ta"}, {"チ", "chi"}, {"ツ", "tsu"}, {"テ", "te"}, {"ト", "to"},
-	{"ナ", "na"}, {"ニ", "ni"}, {"ヌ", "nu"}, {"ネ", "ne"}, {"ノ", "no"},
-	{"ハ", "ha"}, {"ヒ", "hi"}, {"フ", "fu"}, {"ヘ", "he"}, {"ホ", "ho"},
-	{"マ", "ma"}, {"ミ", "mi"}, {"ム", "mu"}, {"メ", "me"}, {"モ", "mo"},
-	{"ヤ", "ya"}, {"ユ", "yu"}, {"ヨ", "yo"},
-	{"ラ", "ra"}, {"リ", "ri"}, {"ル", "ru"}, {"レ", "re"}, {"ロ", "ro"},
-	{"ワ", "wa"}, {"ヲ", "wo"}, {"ン", "n"},
+	{"ア", "a", RowA, nil}, {"イ", "i", RowA, nil}, {"ウ", "u", RowA, nil}, {"エ", "e", RowA, nil}, {"オ", "o", RowA, nil},
+	{"カ", "ka", RowKa, nil}, {"キ", "ki", RowKa, nil}, {"ク", "ku", RowKa, nil}, {"ケ", "ke", RowKa, nil}, {"コ", "ko", RowKa, nil},
+	{"サ", "sa", RowSa, nil}, {"シ", "shi", RowSa, []string{"si"}}, {"ス", "su", RowSa, nil}, {"セ", "se", RowSa, nil}, {"ソ", "so", RowSa, nil},
+	{"タ", "ta", RowTa, nil}, {"チ", "chi", RowTa, []string{"ti"}}, {"ツ", "tsu", RowTa, []string{"tu"}}, {"テ", "te", RowTa, nil}, {"ト", "to", RowTa, nil},
+	{"ナ", "na", RowNa, nil}, {"ニ", "ni", RowNa, nil}, {"ヌ", "nu", RowNa, nil}, {"ネ", "ne", RowNa, nil}, {"ノ", "no", RowNa, nil},
+	{"ハ", "ha", RowHa, nil}, {"ヒ", "hi", RowHa, nil}, {"フ", "fu", RowHa, []string{"hu"}}, {"ヘ", "he", RowHa, nil}, {"ホ", "ho", RowHa, nil},
+	{"マ", "ma", RowMa, nil}, {"ミ", "mi", RowMa, nil}, {"ム", "mu", RowMa, nil}, {"メ", "me", RowMa, nil}, {"モ", "mo", RowMa, nil},
+	{"ヤ", "ya", RowYa, nil}, {"ユ", "yu", RowYa, nil}, {"ヨ", "yo", RowYa, nil},
+	{"ラ", "ra", RowRa, nil}, {"リ", "ri", RowRa, nil}, {"ル", "ru", RowRa, nil}, {"レ", "re", RowRa, nil}, {"ロ", "ro", RowRa, nil},
+	{"ワ", "wa", RowWa, nil}, {"ヲ", "wo", RowWa, nil}, {"ン", "n", RowWa, []string{"nn"}},
 }
 
 // DakutenKatakana contains katakana characters with dakuten (゛) marks
 var DakutenKatakana = []Kana{
-	{"ガ", "ga"}, {"ギ", "gi"}, {"グ", "gu"}, {"ゲ", "ge"}, {"ゴ", "go"},
-	{"ザ", "za"}, {"ジ", "ji"}, {"ズ", "zu"}, {"ゼ", "ze"}, {"ゾ", "zo"},
-	{"ダ", "da"}, {"ヂ", "di"}, {"ヅ", "du"}, {"デ", "de"}, {"ド", "do"},
-	{"バ", "ba"}, {"ビ", "bi"}, {"ブ", "bu"}, {"ベ", "be"}, {"ボ", "bo"},
-	{"ヴ", "vu"},
+	{"ガ", "ga", RowGa, nil}, {"ギ", "gi", RowGa, nil}, {"グ", "gu", RowGa, nil}, {"ゲ", "ge", RowGa, nil}, {"ゴ", "go", RowGa, nil},
+	{"ザ", "za", RowZa, nil}, {"ジ", "ji", RowZa, []string{"zi"}}, {"ズ", "zu", RowZa, nil}, {"ゼ", "ze", RowZa, nil}, {"ゾ", "zo", RowZa, nil},
+	{"ダ", "da", RowDa, nil}, {"ヂ", "di", RowDa, []string{"ji", "zi"}}, {"ヅ", "du", RowDa, []string{"zu"}}, {"デ", "de", RowDa, nil}, {"ド", "do", RowDa, nil},
+	{"バ", "ba", RowBa, nil}, {"ビ", "bi", RowBa, nil}, {"ブ", "bu", RowBa, nil}, {"ベ", "be", RowBa, nil}, {"ボ", "bo", RowBa, nil},
+	// ヴ is ウ with dakuten, but no dakuten row of the table holds it
+	{"ヴ", "vu", RowNone, nil},
 }
 
 // HandakutenKatakana contains katakana characters with handakuten (゜) marks
 var HandakutenKatakana = []Kana{
-	{"パ", "pa"}, {"ピ", "pi"}, {"プ", "pu"}, {"ペ", "pe"}, {"ポ", "po"},
+	{"パ", "pa", RowPa, nil}, {"ピ", "pi", RowPa, nil}, {"プ", "pu", RowPa, nil}, {"ペ", "pe", RowPa, nil}, {"ポ", "po", RowPa, nil},
 }
 
-// GetKanaSet returns the appropriate kana slice based on the selected type and dakuten setting
-func GetKanaSet(kanaType KanaType, includeDakuten bool) []Kana {
-	switch kanaType {
-	case KanaTypeHiragana:
-		if includeDakuten {
-			combined := make([]Kana, 0, len(MainHiragana)+len(DakutenHiragana)+len(HandakutenHiragana))
-			combined = append(combined, MainHiragana...)
-			combined = append(combined, DakutenHiragana...)
-			combined = append(combined, HandakutenHiragana...)
-			return combined
+// YoonHiragana contains hiragana contracted sounds (small ゃ, ゅ, ょ)
+var YoonHiragana = []Kana{
+	{"きゃ", "kya", RowKa, nil}, {"きゅ", "kyu", RowKa, nil}, {"きょ", "kyo", RowKa, nil},
+	{"しゃ", "sha", RowSa, []string{"sya"}}, {"しゅ", "shu", RowSa, []string{"syu"}}, {"しょ", "sho", RowSa, []string{"syo"}},
+	{"ちゃ", "cha", RowTa, []string{"tya", "cya"}}, {"ちゅ", "chu", RowTa, []string{"tyu", "cyu"}}, {"ちょ", "cho", RowTa, []string{"tyo", "cyo"}},
+	{"にゃ", "nya", RowNa, nil}, {"にゅ", "nyu", RowNa, nil}, {"にょ", "nyo", RowNa, nil},
+	{"ひゃ", "hya", RowHa, nil}, {"ひゅ", "hyu", RowHa, nil}, {"ひょ", "hyo", RowHa, nil},
+	{"みゃ", "mya", RowMa, nil}, {"みゅ", "myu", RowMa, nil}, {"みょ", "myo", RowMa, nil},
+	{"りゃ", "rya", RowRa, nil}, {"りゅ", "ryu", RowRa, nil}, {"りょ", "ryo", RowRa, nil},
+}
+
+// DakutenYoonHiragana contains hiragana contracted sounds with dakuten or handakuten
+var DakutenYoonHiragana = []Kana{
+	{"ぎゃ", "gya", RowGa, nil}, {"ぎゅ", "gyu", RowGa, nil}, {"ぎょ", "gyo", RowGa, nil},
+	{"じゃ", "ja", RowZa, []string{"zya", "jya"}}, {"じゅ", "ju", RowZa, []string{"zyu", "jyu"}}, {"じょ", "jo", RowZa, []string{"zyo", "jyo"}},
+	{"びゃ", "bya", RowBa, nil}, {"びゅ", "byu", RowBa, nil}, {"びょ", "byo", RowBa, nil},
+	{"ぴゃ", "pya", RowPa, nil}, {"ぴゅ", "pyu", RowPa, nil}, {"ぴょ", "pyo", RowPa, nil},
+}
+
+// YoonKatakana contains katakana contracted sounds (small ャ, ュ, ョ)
+var YoonKatakana = []Kana{
+	{"キャ", "kya", RowKa, nil}, {"キュ", "kyu", RowKa, nil}, {"キョ", "kyo", RowKa, nil},
+	{"シャ", "sha", RowSa, []string{"sya"}}, {"シュ", "shu", RowSa, []string{"syu"}}, {"ショ", "sho", RowSa, []string{"syo"}},
+	{"チャ", "cha", RowTa, []string{"tya", "cya"}}, {"チュ", "chu", RowTa, []string{"tyu", "cyu"}}, {"チョ", "cho", RowTa, []string{"tyo", "cyo"}},
+	{"ニャ", "nya", RowNa, nil}, {"ニュ", "nyu", RowNa, nil}, {"ニョ", "nyo", RowNa, nil},
+	{"ヒャ", "hya", RowHa, nil}, {"ヒュ", "hyu", RowHa, nil}, {"ヒョ", "hyo", RowHa, nil},
+	{"ミャ", "mya", RowMa, nil}, {"ミュ", "myu", RowMa, nil}, {"ミョ", "myo", RowMa, nil},
+	{"リャ", "rya", RowRa, nil}, {"リュ", "ryu", RowRa, nil}, {"リョ", "ryo", RowRa, nil},
+}
+
+// DakutenYoonKatakana contains katakana contracted sounds with dakuten or handakuten
+var DakutenYoonKatakana = []Kana{
+	{"ギャ", "gya", RowGa, nil}, {"ギュ", "gyu", RowGa, nil}, {"ギョ", "gyo", RowGa, nil},
+	{"ジャ", "ja", RowZa, []string{"zya", "jya"}}, {"ジュ", "ju", RowZa, []string{"zyu", "jyu"}}, {"ジョ", "jo", RowZa, []string{"zyo", "jyo"}},
+	{"ビャ", "bya", RowBa, nil}, {"ビュ", "byu", RowBa, nil}, {"ビョ", "byo", RowBa, nil},
+	{"ピャ", "pya", RowPa, nil}, {"ピュ", "pyu", RowPa, nil}, {"ピョ", "pyo", RowPa, nil},
+}
+
+// GetKanaSet returns the appropriate kana slice based on the selected type, dakuten and yōon settings,
+// restricted to the selected gojūon rows. A nil rows selection includes every row.
+func GetKanaSet(kanaType KanaType, includeDakuten bool, includeYoon bool, rows map[KanaRow]bool) []Kana {
+	var combined []Kana
+	if kanaType == KanaTypeHiragana || kanaType == KanaTypeBoth {
+		combined = append(combined, hiraganaSet(includeDakuten, includeYoon)...)
+	}
+	if kanaType == KanaTypeKatakana || kanaType == KanaTypeBoth {
+		combined = append(combined, katakanaSet(includeDakuten, includeYoon)...)
+	}
+	if len(combined) == 0 {
+		combined = MainHiragana
+	}
+	if rows == nil {
+		return combined
+	}
+
+	all := true
+	for r := KanaRow(0); r < KanaRowCount; r++ {
+		all = all && rows[r]
+	}
+	filtered := make([]Kana, 0, len(combined))
+	for _, k := range combined {
+		if rows[k.Row] || (k.Row == RowNone && all) {
+			filtered = append(filtered, k)
 		}
-		return MainHiragana
-	case KanaTypeKatakana:
+	}
+	return filtered
+}
+
+func hiraganaSet(includeDakuten bool, includeYoon bool) []Kana {
+	combined := append([]Kana{}, MainHiragana...)
+	if includeDakuten {
+		combined = append(combined, DakutenHiragana...)
+		combined = append(combined, HandakutenHiragana...)
+	}
+	if includeYoon {
+		combined = append(combined, YoonHiragana...)
 		if includeDakuten {
-			combined := make([]Kana, 0, len(MainKatakana)+len(DakutenKatakana)+len(HandakutenKatakana))
-			combined = append(combined, MainKatakana...)
-			combined = append(combined, DakutenKatakana...)
-			combined = append(combined, HandakutenKatakana...)
-			return combined
+			combined = append(combined, DakutenYoonHiragana...)
 		}
-		return MainKatakana
-	case KanaTypeBoth:
+	}
+	return combined
+}
+
+func katakanaSet(includeDakuten bool, includeYoon bool) []Kana {
+	combined := append([]Kana{}, MainKatakana...)
+	if includeDakuten {
+		combined = append(combined, DakutenKatakana...)
+		combined = append(combined, HandakutenKatakana...)
+	}
+	if includeYoon {
+		combined = append(combined, YoonKatakana...)
 		if includeDakuten {
-			combined := make([]Kana, 0, len(MainHiragana)+len(DakutenHiragana)+len(HandakutenHiragana)+len(MainKatakana)+len(DakutenKatakana)+len(HandakutenKatakana))
-			combined = append(combined, MainHiragana...)
-			combined = append(combined, DakutenHiragana...)
-			combined = append(combined, HandakutenHiragana...)
-			combined = append(combined, MainKatakana...)
-			combined = append(combined, DakutenKatakana...)
-			combined = append(combined, HandakutenKatakana...)
-			return combined
+			combined = append(combined, DakutenYoonKatakana...)
 		}
-		combined := make([]Kana, 0, len(MainHiragana)+len(MainKatakana))
-		combined = append(combined, MainHiragana...)
-		combined = append(combined, MainKatakana...)
-		return combined
-	default:
-		return MainHiragana
 	}
+	return combined
 }
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetKanaSetRows(t *testing.T) {
+	hasVu := func(kana []Kana) bool {
+		return slices.ContainsFunc(kana, func(k Kana) bool { return k.Character == "ヴ" })
+	}
+
+	tests := []struct {
+		name string
+		rows map[KanaRow]bool
+		want bool
+	}{
+		{"no filter", nil, true},
+		{"every row", AllRows(), true},
+		{"a-row", map[KanaRow]bool{RowA: true}, false},
+		{"dakuten rows", map[KanaRow]bool{RowGa: true, RowZa: true, RowDa: true, RowBa: true, RowPa: true}, false},
+	}
+	for _, tt := range tests {
+		kana := GetKanaSet(KanaTypeKatakana, true, false, tt.rows)
+		if got := hasVu(kana); got != tt.want {
+			t.Errorf("%s: ヴ included = %v, want %v", tt.name, got, tt.want)
+		}
+		for _, k := range kana {
+			if tt.rows != nil && k.Row != RowNone && !tt.rows[k.Row] {
+				t.Errorf("%s: %s of the %s was included", tt.name, k.Character, k.Row)
+			}
+		}
+	}
+}
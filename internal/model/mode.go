@@ -0,0 +1,142 @@
+package model
+
+import (
+	"slices"
+	"strings"
+
+	"gokana/internal/ime"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+type GameMode int
+
+const (
+	ModeClassic GameMode = iota
+	ModeReverse
+	ModeTyping
+	ModeWords
+	ModeQuiz
+	ModeDrill
+)
+
+// GameModeCount is the number of selectable game modes
+const GameModeCount = 6
+
+func (g GameMode) String() string {
+	switch g {
+	case ModeClassic:
+		return "Classic"
+	case ModeReverse:
+		return "Reverse"
+	case ModeTyping:
+		return "Kana Typing"
+	case ModeWords:
+		return "Words"
+	case ModeQuiz:
+		return "Quiz"
+	case ModeDrill:
+		return "Confusion Drill"
+	default:
+		return "Unknown"
+	}
+}
+
+// Description returns a short explanation of the mode for the menu
+func (g GameMode) Description() string {
+	switch g {
+	case ModeClassic:
+		return "kana falls, type the romaji"
+	case ModeReverse:
+		return "romaji falls, type or pick the kana"
+	case ModeTyping:
+		return "kana falls, type romaji composed into kana"
+	case ModeWords:
+		return "whole words fall, type their romaji"
+	case ModeQuiz:
+		return "no falling, pick the romaji with 1-4"
+	case ModeDrill:
+		return "look-alike kana fall side by side"
+	default:
+		return ""
+	}
+}
+
+// Label returns the text shown for a falling kana in the current mode
+func (m *Model) Label(k Kana) string {
+	if m.Mode == ModeReverse {
+		return Romanize(k, m.Romanization)
+	}
+	return k.Character
+}
+
+// LabelWidth returns the number of terminal cells a falling kana takes
+func (m *Model) LabelWidth(k Kana) int {
+	return ansi.StringWidth(m.Label(k))
+}
+
+// AnswerText returns the expected answer for the kana, for display
+func (m *Model) AnswerText(k Kana) string {
+	if m.Mode == ModeReverse {
+		return k.Character
+	}
+	return Romanize(k, m.Romanization)
+}
+
+// Answers returns the answers accepted for the kana in the current mode.
+// In kana typing mode they are the romaji that compose into the kana, and
+// the listed romaji for deck entries composition cannot produce (kanji). In
+// reverse mode every kana of the pool showing the same romaji is accepted,
+// the player cannot tell ず from づ or し from シ by their label.
+func (m *Model) Answers(k Kana) []string {
+	if m.Mode == ModeReverse {
+		answers := []string{k.Character}
+		label := m.Label(k)
+		for _, p := range m.KanaPool() {
+			if m.Label(p) == label && !slices.Contains(answers, p.Character) {
+				answers = append(answers, p.Character)
+			}
+		}
+		return answers
+	}
+	return m.Romanization.Answers(k)
+}
+
+// Accepts checks if the answer is accepted for the kana in the current mode
+func (m *Model) Accepts(k Kana, answer string) bool {
+	if m.Mode == ModeTyping && ime.IsKana(k.Character) {
+		complete, _ := ime.Matches(answer, k.Character)
+		return complete
+	}
+	if m.Mode == ModeWords {
+		// Any spelling that composes into the word is accepted on top of the listed ones
+		if complete, _ := ime.Matches(answer, k.Character); complete {
+			return true
+		}
+	}
+	for _, a := range m.Answers(k) {
+		if answer == a {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnswerPrefix checks if the input can still lead to an accepted answer for the kana
+func (m *Model) HasAnswerPrefix(k Kana, input string) bool {
+	if m.Mode == ModeTyping && ime.IsKana(k.Character) {
+		_, prefix := ime.Matches(input, k.Character)
+		return prefix
+	}
+	if m.Mode == ModeWords {
+		if _, prefix := ime.Matches(input, k.Character); prefix {
+			return true
+		}
+	}
+	for _, a := range m.Answers(k) {
+		if strings.HasPrefix(a, input) {
+			return true
+		}
+	}
+	return false
+}
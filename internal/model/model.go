@@ -1,60 +1,480 @@
 package model
 
-import "time"
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"gokana/internal/scores"
+	"gokana/internal/srs"
+	"gokana/internal/stats"
+)
 
 type GameState int
 
 const (
 	StateMenu GameState = iota
 	StatePlaying
+	StatePaused
 	StateGameOver
+	StateLeaderboard
 	StateQuitting
 )
 
 type MenuSection int
 
 const (
-	MenuSectionKana MenuSection = iota
+	MenuSectionMode MenuSection = iota
+	MenuSectionKana
 	MenuSectionDakuten
+	MenuSectionYoon
+	MenuSectionRows
+	MenuSectionRomanization
+	MenuSectionSpawn
 	MenuSectionLevel
+	MenuSectionTimeAttack
+	MenuSectionZen
 	MenuSectionLives
 	MenuSectionStart
+	MenuSectionDaily
+	MenuSectionScores
+)
+
+type SpawnMode int
+
+const (
+	SpawnUniform SpawnMode = iota
+	SpawnAdaptive
+)
+
+func (s SpawnMode) String() string {
+	switch s {
+	case SpawnUniform:
+		return "Uniform"
+	case SpawnAdaptive:
+		return "Adaptive"
+	default:
+		return "Unknown"
+	}
+}
+
+// TimeAttack is the fixed length of a timed run, during which lives are disabled
+type TimeAttack int
+
+const (
+	TimeAttackOff TimeAttack = iota
+	TimeAttack60
+	TimeAttack120
+	TimeAttack300
+)
+
+// TimeAttackCount is the number of time attack options
+const TimeAttackCount = 4
+
+func (t TimeAttack) String() string {
+	switch t {
+	case TimeAttackOff:
+		return "Off"
+	case TimeAttack60:
+		return "60s"
+	case TimeAttack120:
+		return "120s"
+	case TimeAttack300:
+		return "300s"
+	default:
+		return "Unknown"
+	}
+}
+
+// Duration returns the length of the run, zero when time attack is off
+func (t TimeAttack) Duration() time.Duration {
+	switch t {
+	case TimeAttack60:
+		return 60 * time.Second
+	case TimeAttack120:
+		return 120 * time.Second
+	case TimeAttack300:
+		return 300 * time.Second
+	default:
+		return 0
+	}
+}
+
+type GameOverOption int
+
+const (
+	GameOverReplay GameOverOption = iota
+	GameOverPractice
+	GameOverMenu
+	GameOverQuit
 )
 
+// GameOverOptionCount is the number of options on the results screen
+const GameOverOptionCount = 4
+
+func (o GameOverOption) String() string {
+	switch o {
+	case GameOverReplay:
+		return "Play Again"
+	case GameOverPractice:
+		return "Practice Missed Kana"
+	case GameOverMenu:
+		return "Back to Menu"
+	case GameOverQuit:
+		return "Quit"
+	default:
+		return "Unknown"
+	}
+}
+
+type PauseOption int
+
+const (
+	PauseResume PauseOption = iota
+	PauseRestart
+	PauseMenu
+)
+
+// PauseOptionCount is the number of options on the pause overlay
+const PauseOptionCount = 3
+
+func (o PauseOption) String() string {
+	switch o {
+	case PauseResume:
+		return "Resume"
+	case PauseRestart:
+		return "Restart"
+	case PauseMenu:
+		return "Quit to Menu"
+	default:
+		return "Unknown"
+	}
+}
+
+// Mistake records how often a kana was missed or mistyped during a run
+type Mistake struct {
+	Kana     Kana
+	Misses   int
+	Mistyped int
+}
+
+// MinTerminalWidth and MinTerminalHeight are the smallest terminal the game can be played in
+const (
+	MinTerminalWidth  = 60
+	MinTerminalHeight = 22
+
+	// The daily challenge plays on a fixed 60×8 field so everyone gets the
+	// same kana positions, whatever the size of their terminal
+	DailyMinTerminalWidth  = 63
+	DailyMinTerminalHeight = 24
+)
+
+// MinTerminalSize returns the smallest terminal the current run can be played in
+func (m *Model) MinTerminalSize() (width, height int) {
+	if m.Daily != "" {
+		return DailyMinTerminalWidth, DailyMinTerminalHeight
+	}
+	return MinTerminalWidth, MinTerminalHeight
+}
+
 // Model represents the game state
 type Model struct {
-	State           GameState
-	SelectedKana    KanaType
-	DakutenEnabled  bool
-	MenuCursor      int
-	MenuSection     MenuSection
-	StartLevel      int
-	StartLives      int
-	FallingKanas    []FallingKana
-	Input           string
-	Feedback        string
-	FeedbackType    string
-	Correct         int
-	Total           int
-	LevelOffset     int
-	Lives           int
-	Quitting        bool
-	GameOver        bool
-	MaxFallHeight   int
-	PlayAreaWidth   int
-	ShowingFeedback bool
-	FallSpeed       time.Duration
-	TimeAccumulated time.Duration
-}
-
-// GetLevel returns the current level based on correct answers and starting level offset
+	State            GameState
+	Mode             GameMode
+	SelectedKana     KanaType
+	SelectedDeck     int
+	Decks            []Deck
+	DeckErrors       []string
+	DakutenEnabled   bool
+	YoonEnabled      bool
+	Romanization     RomanizationSystem
+	SelectedRows     map[KanaRow]bool
+	SpawnMode        SpawnMode
+	PickingRows      bool
+	RowCursor        int
+	MenuCursor       int
+	MenuSection      MenuSection
+	StartLevel       int
+	StartLives       int
+	TimeAttack       TimeAttack
+	Zen              bool
+	Daily            string
+	SavedSettings    *Settings
+	Seed             int64
+	Rand             *rand.Rand
+	FallingKanas     []FallingKana
+	Candidates       []Kana
+	QuizQuestion     FallingKana
+	QuizOptions      []Kana
+	QuizAnswer       int
+	Input            string
+	Feedback         string
+	FeedbackType     string
+	Correct          int
+	Total            int
+	WrongInputs      int
+	Streak           int
+	MaxStreak        int
+	Points           int
+	Mistakes         []Mistake
+	PracticeKana     []Kana
+	StartedAt        time.Time
+	EndedAt          time.Time
+	Now              time.Time
+	GameOverCursor   GameOverOption
+	PausedAt         time.Time
+	PausedTotal      time.Duration
+	PauseCursor      PauseOption
+	TickGeneration   int
+	LevelOffset      int
+	Lives            int
+	Quitting         bool
+	GameOver         bool
+	MaxFallHeight    int
+	PlayAreaWidth    int
+	TermWidth        int
+	TermHeight       int
+	TerminalTooSmall bool
+	ShowingFeedback  bool
+	FallSpeed        time.Duration
+	TimeAccumulated  time.Duration
+	Stats            *stats.Store
+	StatsError       string
+	Scheduler        *srs.Scheduler
+	SchedulerError   string
+	StoresDirty      bool
+	Scores           *scores.Board
+	ScoresError      string
+	SettingsError    string
+	ScoreRank        int
+	ReplayPath       string
+	ReplayError      string
+	Replaying        bool
+	BoardConfigs     []scores.Config
+	BoardCursor      int
+}
+
+// GetLevel returns the current level based on correct answers and starting
+// level offset. Zen mode stays at the starting level.
 func (m *Model) GetLevel() int {
+	if m.Zen {
+		return 1 + m.LevelOffset
+	}
 	return (m.Correct / 20) + 1 + m.LevelOffset
 }
 
+// FieldSize returns how many kana are kept falling at once. The confusion
+// drill needs at least two so look-alikes can be told apart side by side.
+func (m *Model) FieldSize() int {
+	if m.Mode == ModeDrill {
+		return max(m.GetLevel(), 2)
+	}
+	return m.GetLevel()
+}
+
+// Timed checks if the run is a time attack
+func (m *Model) Timed() bool {
+	return m.TimeAttack != TimeAttackOff
+}
+
+// LivesEnabled checks if misses cost a life, they don't in time attack and zen mode
+func (m *Model) LivesEnabled() bool {
+	return !m.Timed() && !m.Zen
+}
+
+// Remaining returns the time left in a time attack
+func (m *Model) Remaining() time.Duration {
+	return max(m.TimeAttack.Duration()-m.Duration(), 0)
+}
+
+// AnswersPerMinute returns the rate of correct answers over the run
+func (m *Model) AnswersPerMinute() float64 {
+	d := m.Duration()
+	if m.Timed() {
+		d = min(d, m.TimeAttack.Duration())
+	}
+	if d <= 0 {
+		return 0
+	}
+	return float64(m.Correct) / d.Minutes()
+}
+
+// Scoring: every correct answer is worth BasePoints plus a bonus for how high
+// the kana still was, times the streak multiplier
+const (
+	BasePoints     = 100
+	MaxHeightBonus = 50
+
+	// StreakPerMultiplier consecutive correct answers raise the multiplier by one, up to MaxMultiplier
+	StreakPerMultiplier = 5
+	MaxMultiplier       = 4
+)
+
 // GetPoints returns the current points
 func (m *Model) GetPoints() int {
-	return m.Correct * 100
+	return m.Points
+}
+
+// Multiplier returns the score multiplier of the current streak
+func (m *Model) Multiplier() int {
+	return min(1+m.Streak/StreakPerMultiplier, MaxMultiplier)
+}
+
+// HeightBonus returns the bonus for answering fk at its current height: the
+// full bonus right as it spawns, none at the bottom. Quiz questions don't fall.
+func (m *Model) HeightBonus(fk FallingKana) int {
+	if m.Mode == ModeQuiz || m.MaxFallHeight <= 0 {
+		return 0
+	}
+	return MaxHeightBonus * max(m.MaxFallHeight-fk.FallPosition, 0) / m.MaxFallHeight
+}
+
+// AnswerPoints returns what a correct answer on fk is worth at the current streak
+func (m *Model) AnswerPoints(fk FallingKana) int {
+	return (BasePoints + m.HeightBonus(fk)) * m.Multiplier()
+}
+
+// KanaPool returns the characters the current configuration draws from
+func (m *Model) KanaPool() []Kana {
+	if len(m.PracticeKana) > 0 {
+		return m.PracticeKana
+	}
+	if m.SelectedKana == KanaTypeDeck && m.SelectedDeck >= 0 && m.SelectedDeck < len(m.Decks) {
+		return m.Decks[m.SelectedDeck].Kana
+	}
+	if m.Mode == ModeWords {
+		return GetWordSet(m.SelectedKana)
+	}
+	pool := GetKanaSet(m.SelectedKana, m.DakutenEnabled, m.YoonEnabled, m.SelectedRows)
+	if len(pool) == 0 {
+		// The selected rows have no kana with the current settings (e.g. only
+		// dakuten rows with dakuten disabled), fall back to every row
+		return GetKanaSet(m.SelectedKana, m.DakutenEnabled, m.YoonEnabled, nil)
+	}
+	return pool
+}
+
+// SetName returns the display name of the selected character set
+func (m *Model) SetName() string {
+	if len(m.PracticeKana) > 0 {
+		return "Missed Kana"
+	}
+	if m.SelectedKana == KanaTypeDeck && m.SelectedDeck >= 0 && m.SelectedDeck < len(m.Decks) {
+		return m.Decks[m.SelectedDeck].Name
+	}
+	return m.SelectedKana.String()
+}
+
+// ScoreConfig returns the configuration the current run is ranked under
+func (m *Model) ScoreConfig() scores.Config {
+	if m.Daily != "" {
+		return DailyScoreConfig(m.Daily)
+	}
+	config := scores.Config{KanaSet: m.SetName(), StartLevel: m.StartLevel}
+	if m.LivesEnabled() {
+		config.StartLives = m.StartLives
+	}
+	if m.SelectedKana != KanaTypeDeck && m.Mode != ModeWords {
+		config.Dakuten = m.DakutenEnabled
+		config.Yoon = m.YoonEnabled
+		config.Rows = m.rowsName()
+	}
+	// Classic scores keep an empty mode so older score files still match
+	if m.Mode != ModeClassic {
+		config.Mode = m.Mode.String()
+	}
+	config.TimeLimit = int(m.TimeAttack.Duration().Seconds())
+	return config
+}
+
+// DailyScoreConfig returns the configuration the daily challenge of date is
+// ranked under. Its settings are fixed, only the day tells runs apart.
+func DailyScoreConfig(date string) scores.Config {
+	s := DailySettings()
+	return scores.Config{Daily: date, StartLevel: s.StartLevel, StartLives: s.StartLives}
+}
+
+// rowsName lists the selected gojūon rows that can spawn, empty when all of them can
+func (m *Model) rowsName() string {
+	var selected []string
+	all := true
+	for r := KanaRow(0); r < KanaRowCount; r++ {
+		if r.IsDakuten() && !m.DakutenEnabled {
+			continue
+		}
+		if m.SelectedRows[r] {
+			selected = append(selected, kanaRowNames[r].hiragana)
+		} else {
+			all = false
+		}
+	}
+	if all {
+		return ""
+	}
+	return strings.Join(selected, " ")
+}
+
+// KanaOptionCount returns the number of character set options in the menu
+func (m *Model) KanaOptionCount() int {
+	return BuiltinKanaOptions + len(m.Decks)
+}
+
+// KanaOptionIndex returns the menu option index of the selected character set
+func (m *Model) KanaOptionIndex() int {
+	if m.SelectedKana == KanaTypeDeck {
+		return BuiltinKanaOptions + m.SelectedDeck
+	}
+	return int(m.SelectedKana)
+}
+
+// Accuracy returns the share of correct answers among answers, misses and wrong inputs
+func (m *Model) Accuracy() float64 {
+	attempts := m.Total + m.WrongInputs
+	if attempts == 0 {
+		return 0
+	}
+	return float64(m.Correct) / float64(attempts)
+}
+
+// Duration returns how long the current or last run lasted, as of the
+// engine's last update
+func (m *Model) Duration() time.Duration {
+	if m.StartedAt.IsZero() {
+		return 0
+	}
+	end := m.EndedAt
+	if end.IsZero() {
+		end = m.Now
+	}
+	if m.State == StatePaused {
+		end = m.PausedAt
+	}
+	return end.Sub(m.StartedAt) - m.PausedTotal
+}
+
+// RecordMistake adds a miss or a wrong input on the kana to the run's mistakes
+func (m *Model) RecordMistake(k Kana, missed bool) {
+	i := 0
+	for i < len(m.Mistakes) && m.Mistakes[i].Kana.Character != k.Character {
+		i++
+	}
+	if i == len(m.Mistakes) {
+		m.Mistakes = append(m.Mistakes, Mistake{Kana: k})
+	}
+	if missed {
+		m.Mistakes[i].Misses++
+	} else {
+		m.Mistakes[i].Mistyped++
+	}
+}
+
+// MistakeKana returns every kana missed or mistyped during the run
+func (m *Model) MistakeKana() []Kana {
+	kana := make([]Kana, 0, len(m.Mistakes))
+	for _, mistake := range m.Mistakes {
+		kana = append(kana, mistake.Kana)
+	}
+	return kana
 }
 
 // HasShowingCorrect checks if any kana is showing as correct
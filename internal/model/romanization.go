@@ -0,0 +1,122 @@
+package model
+
+import "slices"
+
+type RomanizationSystem int
+
+const (
+	RomanizationAny RomanizationSystem = iota
+	RomanizationHepburn
+	RomanizationKunrei
+	RomanizationNihon
+)
+
+// RomanizationSystemCount is the number of selectable romanization systems
+const RomanizationSystemCount = 4
+
+func (r RomanizationSystem) String() string {
+	switch r {
+	case RomanizationAny:
+		return "Any"
+	case RomanizationHepburn:
+		return "Hepburn"
+	case RomanizationKunrei:
+		return "Kunrei-shiki"
+	case RomanizationNihon:
+		return "Nihon-shiki"
+	default:
+		return "Unknown"
+	}
+}
+
+// romanization holds the spelling of a syllable in each strict system
+type romanization struct {
+	hepburn string
+	kunrei  string
+	nihon   string
+}
+
+// romanizationTable maps canonical romaji to the spelling of each system.
+// Syllables spelled identically in every system are not listed.
+var romanizationTable = map[string]romanization{
+	"shi": {"shi", "si", "si"},
+	"chi": {"chi", "ti", "ti"},
+	"tsu": {"tsu", "tu", "tu"},
+	"fu":  {"fu", "hu", "hu"},
+	"ji":  {"ji", "zi", "zi"},
+	"sha": {"sha", "sya", "sya"},
+	"shu": {"shu", "syu", "syu"},
+	"sho": {"sho", "syo", "syo"},
+	"cha": {"cha", "tya", "tya"},
+	"chu": {"chu", "tyu", "tyu"},
+	"cho": {"cho", "tyo", "tyo"},
+	"ja":  {"ja", "zya", "zya"},
+	"ju":  {"ju", "zyu", "zyu"},
+	"jo":  {"jo", "zyo", "zyo"},
+}
+
+// characterRomanizationTable covers kana whose spelling depends on the
+// character itself rather than its canonical romaji (ぢ/じ, づ/ず, を/お).
+var characterRomanizationTable = map[string]romanization{
+	"ぢ": {"ji", "zi", "di"},
+	"ヂ": {"ji", "zi", "di"},
+	"づ": {"zu", "zu", "du"},
+	"ヅ": {"zu", "zu", "du"},
+	"を": {"o", "o", "wo"},
+	"ヲ": {"o", "o", "wo"},
+}
+
+func (r romanization) spelling(system RomanizationSystem) string {
+	switch system {
+	case RomanizationKunrei:
+		return r.kunrei
+	case RomanizationNihon:
+		return r.nihon
+	default:
+		return r.hepburn
+	}
+}
+
+// spellings returns the spelling of the kana in each strict system, false
+// for kana spelled the same in all of them
+func spellings(k Kana) (romanization, bool) {
+	if r, ok := characterRomanizationTable[k.Character]; ok {
+		return r, true
+	}
+	r, ok := romanizationTable[k.Romaji]
+	return r, ok
+}
+
+// has checks if s is the spelling of one of the systems
+func (r romanization) has(s string) bool {
+	return s == r.hepburn || s == r.kunrei || s == r.nihon
+}
+
+// Romanize returns the spelling of the kana in the given system
+func Romanize(k Kana, system RomanizationSystem) string {
+	if system == RomanizationAny {
+		return k.Romaji
+	}
+	if r, ok := spellings(k); ok {
+		return r.spelling(system)
+	}
+	return k.Romaji
+}
+
+// Answers returns the spellings accepted for the kana in this system.
+// Any accepts every known spelling. The other systems only accept their own
+// spelling of a syllable the systems disagree on, but keep the alternates
+// that are not another system's spelling, such as the readings a deck lists.
+func (r RomanizationSystem) Answers(k Kana) []string {
+	s, ok := spellings(k)
+	if r == RomanizationAny || !ok {
+		return k.Answers()
+	}
+	answers := []string{s.spelling(r)}
+	for _, a := range k.Alternates {
+		if !s.has(a) && !slices.Contains(answers, a) {
+			answers = append(answers, a)
+		}
+	}
+	return answers
+}
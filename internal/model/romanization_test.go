@@ -0,0 +1,82 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRomanize(t *testing.T) {
+	shi := Kana{Character: "し", Romaji: "shi", Alternates: []string{"si"}}
+	ji := Kana{Character: "ぢ", Romaji: "di", Alternates: []string{"ji", "zi"}}
+	zu := Kana{Character: "ヅ", Romaji: "du", Alternates: []string{"zu"}}
+	wo := Kana{Character: "を", Romaji: "wo"}
+	sha := Kana{Character: "しゃ", Romaji: "sha"}
+	ka := Kana{Character: "か", Romaji: "ka"}
+
+	tests := []struct {
+		kana   Kana
+		system RomanizationSystem
+		want   string
+	}{
+		{shi, RomanizationAny, "shi"},
+		{shi, RomanizationHepburn, "shi"},
+		{shi, RomanizationKunrei, "si"},
+		{shi, RomanizationNihon, "si"},
+		{ji, RomanizationHepburn, "ji"},
+		{ji, RomanizationKunrei, "zi"},
+		{ji, RomanizationNihon, "di"},
+		{zu, RomanizationHepburn, "zu"},
+		{zu, RomanizationNihon, "du"},
+		{wo, RomanizationHepburn, "o"},
+		{wo, RomanizationNihon, "wo"},
+		{sha, RomanizationKunrei, "sya"},
+		{ka, RomanizationHepburn, "ka"},
+		{ka, RomanizationNihon, "ka"},
+	}
+	for _, tt := range tests {
+		if got := Romanize(tt.kana, tt.system); got != tt.want {
+			t.Errorf("Romanize(%s, %v) = %q, want %q", tt.kana.Character, tt.system, got, tt.want)
+		}
+	}
+}
+
+func TestRomanizationAnswers(t *testing.T) {
+	shi := Kana{Character: "し", Romaji: "shi", Alternates: []string{"si"}}
+
+	tests := []struct {
+		system RomanizationSystem
+		want   []string
+	}{
+		{RomanizationAny, []string{"shi", "si"}},
+		{RomanizationHepburn, []string{"shi"}},
+		{RomanizationKunrei, []string{"si"}},
+	}
+	for _, tt := range tests {
+		if got := tt.system.Answers(shi); !slices.Equal(got, tt.want) {
+			t.Errorf("%v answers for し = %v, want %v", tt.system, got, tt.want)
+		}
+	}
+}
+
+func TestRomanizationAnswersKeepDeckAlternates(t *testing.T) {
+	nichi := Kana{Character: "日", Romaji: "nichi", Alternates: []string{"hi", "ni"}}
+	shi := Kana{Character: "四", Romaji: "shi", Alternates: []string{"si", "yon"}}
+
+	tests := []struct {
+		kana   Kana
+		system RomanizationSystem
+		want   []string
+	}{
+		// Spelled the same in every system, every listed reading stays
+		{nichi, RomanizationHepburn, []string{"nichi", "hi", "ni"}},
+		{nichi, RomanizationNihon, []string{"nichi", "hi", "ni"}},
+		// Respelled, si is the Kunrei-shiki spelling but yon is a reading
+		{shi, RomanizationHepburn, []string{"shi", "yon"}},
+		{shi, RomanizationKunrei, []string{"si", "yon"}},
+	}
+	for _, tt := range tests {
+		if got := tt.system.Answers(tt.kana); !slices.Equal(got, tt.want) {
+			t.Errorf("%v answers for %s = %v, want %v", tt.system, tt.kana.Character, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import "time"
+
+// Settings holds the choices made in the menu
+type Settings struct {
+	Mode           GameMode           `json:"mode"`
+	SelectedKana   KanaType           `json:"kana"`
+	SelectedDeck   int                `json:"-"`
+	DakutenEnabled bool               `json:"dakuten"`
+	YoonEnabled    bool               `json:"yoon"`
+	Romanization   RomanizationSystem `json:"romanization"`
+	SelectedRows   map[KanaRow]bool   `json:"rows"`
+	SpawnMode      SpawnMode          `json:"spawn"`
+	StartLevel     int                `json:"level"`
+	StartLives     int                `json:"lives"`
+	TimeAttack     TimeAttack         `json:"time_attack"`
+	Zen            bool               `json:"zen"`
+}
+
+// Settings returns a copy of the current menu settings
+func (m *Model) Settings() Settings {
+	rows := make(map[KanaRow]bool, len(m.SelectedRows))
+	for r, selected := range m.SelectedRows {
+		rows[r] = selected
+	}
+	return Settings{
+		Mode:           m.Mode,
+		SelectedKana:   m.SelectedKana,
+		SelectedDeck:   m.SelectedDeck,
+		DakutenEnabled: m.DakutenEnabled,
+		YoonEnabled:    m.YoonEnabled,
+		Romanization:   m.Romanization,
+		SelectedRows:   rows,
+		SpawnMode:      m.SpawnMode,
+		StartLevel:     m.StartLevel,
+		StartLives:     m.StartLives,
+		TimeAttack:     m.TimeAttack,
+		Zen:            m.Zen,
+	}
+}
+
+// ApplySettings replaces the menu settings, moving the character set cursor along
+func (m *Model) ApplySettings(s Settings) {
+	m.Mode = s.Mode
+	m.SelectedKana = s.SelectedKana
+	m.SelectedDeck = s.SelectedDeck
+	m.DakutenEnabled = s.DakutenEnabled
+	m.YoonEnabled = s.YoonEnabled
+	m.Romanization = s.Romanization
+	m.SelectedRows = s.SelectedRows
+	m.SpawnMode = s.SpawnMode
+	m.StartLevel = s.StartLevel
+	m.StartLives = s.StartLives
+	m.TimeAttack = s.TimeAttack
+	m.Zen = s.Zen
+	m.MenuCursor = m.KanaOptionIndex()
+}
+
+// DefaultSettings are the menu settings of a first start, and the ones the
+// menu resets to
+func DefaultSettings() Settings {
+	return Settings{
+		Mode:           ModeClassic,
+		SelectedKana:   KanaTypeBoth,
+		DakutenEnabled: true,
+		Romanization:   RomanizationAny,
+		SelectedRows:   AllRows(),
+		SpawnMode:      SpawnUniform,
+		StartLevel:     1,
+		StartLives:     4,
+	}
+}
+
+// DailyDate returns the date the daily challenge of t is recorded under. The
+// day is the UTC one, so teammates in other time zones play the same run.
+func DailyDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+// DailySettings are the fixed settings of the daily challenge, so everyone
+// playing on the same day gets the same run. The settings the challenge
+// announces are pinned, the others are the defaults.
+func DailySettings() Settings {
+	s := DefaultSettings()
+	s.Mode = ModeClassic
+	s.SelectedKana = KanaTypeBoth
+	s.DakutenEnabled = true
+	s.SpawnMode = SpawnUniform
+	s.StartLevel = 1
+	s.StartLives = 4
+	return s
+}
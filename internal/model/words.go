@@ -0,0 +1,59 @@
+package model
+
+// HiraganaWords contains everyday words written in hiragana for word mode
+var HiraganaWords = []Kana{
+	{Character: "さくら", Romaji: "sakura"}, {Character: "ねこ", Romaji: "neko"},
+	{Character: "いぬ", Romaji: "inu"}, {Character: "やま", Romaji: "yama"},
+	{Character: "かわ", Romaji: "kawa"}, {Character: "そら", Romaji: "sora"},
+	{Character: "はな", Romaji: "hana"}, {Character: "みず", Romaji: "mizu"},
+	{Character: "ひと", Romaji: "hito"}, {Character: "くるま", Romaji: "kuruma"},
+	{Character: "たべもの", Romaji: "tabemono"}, {Character: "ともだち", Romaji: "tomodachi"},
+	{Character: "がっこう", Romaji: "gakkou", Alternates: []string{"gakkoo", "gakko"}}, {Character: "きって", Romaji: "kitte"},
+	{Character: "ざっし", Romaji: "zasshi"}, {Character: "にっぽん", Romaji: "nippon"},
+	{Character: "ほん", Romaji: "hon"}, {Character: "しんぶん", Romaji: "shinbun", Alternates: []string{"shimbun"}},
+	{Character: "せんせい", Romaji: "sensei"}, {Character: "きょう", Romaji: "kyou", Alternates: []string{"kyoo", "kyo"}},
+	{Character: "りょこう", Romaji: "ryokou", Alternates: []string{"ryokoo", "ryoko"}}, {Character: "おちゃ", Romaji: "ocha"},
+	{Character: "でんしゃ", Romaji: "densha"}, {Character: "しゅくだい", Romaji: "shukudai"},
+	{Character: "ひこうき", Romaji: "hikouki", Alternates: []string{"hikooki", "hikoki"}}, {Character: "あさ", Romaji: "asa"},
+	{Character: "よる", Romaji: "yoru"}, {Character: "つくえ", Romaji: "tsukue"},
+	{Character: "えき", Romaji: "eki"}, {Character: "かさ", Romaji: "kasa"},
+	{Character: "てがみ", Romaji: "tegami"}, {Character: "みせ", Romaji: "mise"},
+	{Character: "うみ", Romaji: "umi"}, {Character: "ゆき", Romaji: "yuki"},
+	{Character: "あめ", Romaji: "ame"}, {Character: "さかな", Romaji: "sakana"},
+	{Character: "とり", Romaji: "tori"}, {Character: "ちず", Romaji: "chizu"},
+	{Character: "おんな", Romaji: "onna"}, {Character: "きんようび", Romaji: "kinyoubi", Alternates: []string{"kin'youbi", "kinyoobi"}},
+}
+
+// KatakanaWords contains everyday loanwords written in katakana for word mode
+var KatakanaWords = []Kana{
+	{Character: "テレビ", Romaji: "terebi"}, {Character: "コーヒー", Romaji: "koohii", Alternates: []string{"kohi", "kouhii"}},
+	{Character: "パン", Romaji: "pan"}, {Character: "カメラ", Romaji: "kamera"},
+	{Character: "ラジオ", Romaji: "rajio"}, {Character: "ノート", Romaji: "nooto", Alternates: []string{"noto"}},
+	{Character: "ペン", Romaji: "pen"}, {Character: "バス", Romaji: "basu"},
+	{Character: "タクシー", Romaji: "takushii", Alternates: []string{"takushi"}}, {Character: "ホテル", Romaji: "hoteru"},
+	{Character: "メニュー", Romaji: "menyuu", Alternates: []string{"menyu"}}, {Character: "ケーキ", Romaji: "keeki", Alternates: []string{"keki"}},
+	{Character: "ピアノ", Romaji: "piano"}, {Character: "ギター", Romaji: "gitaa", Alternates: []string{"gita"}},
+	{Character: "アイス", Romaji: "aisu"}, {Character: "ゲーム", Romaji: "geemu", Alternates: []string{"gemu"}},
+	{Character: "スポーツ", Romaji: "supootsu", Alternates: []string{"supotsu"}}, {Character: "ベッド", Romaji: "beddo"},
+	{Character: "カップ", Romaji: "kappu"}, {Character: "サッカー", Romaji: "sakkaa", Alternates: []string{"sakka"}},
+	{Character: "チョコレート", Romaji: "chokoreeto", Alternates: []string{"chokoreto"}}, {Character: "ジュース", Romaji: "juusu", Alternates: []string{"jusu"}},
+	{Character: "シャツ", Romaji: "shatsu"}, {Character: "ドア", Romaji: "doa"},
+	{Character: "トマト", Romaji: "tomato"}, {Character: "バナナ", Romaji: "banana"},
+	{Character: "ビール", Romaji: "biiru", Alternates: []string{"biru"}}, {Character: "メール", Romaji: "meeru", Alternates: []string{"meru"}},
+	{Character: "ニュース", Romaji: "nyuusu", Alternates: []string{"nyusu"}}, {Character: "マッチ", Romaji: "macchi", Alternates: []string{"matchi"}},
+}
+
+// GetWordSet returns the built-in words for the selected kana type
+func GetWordSet(kanaType KanaType) []Kana {
+	switch kanaType {
+	case KanaTypeHiragana:
+		return HiraganaWords
+	case KanaTypeKatakana:
+		return KatakanaWords
+	default:
+		combined := make([]Kana, 0, len(HiraganaWords)+len(KatakanaWords))
+		combined = append(combined, HiraganaWords...)
+		combined = append(combined, KatakanaWords...)
+		return combined
+	}
+}
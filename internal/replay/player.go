@@ -0,0 +1,127 @@
+package replay
+
+import (
+	"time"
+
+	"gokana/internal/engine"
+	"gokana/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// frameRate is how often the playback advances
+	frameRate = 50 * time.Millisecond
+
+	// seekStep is how far the arrow keys seek
+	seekStep = 5 * time.Second
+)
+
+type frameMsg struct{}
+
+func frame() tea.Cmd {
+	return tea.Tick(frameRate, func(time.Time) tea.Msg {
+		return frameMsg{}
+	})
+}
+
+// Player plays a recording back through the engine
+type Player struct {
+	rec    *engine.Recording
+	e      *engine.Engine
+	clock  *clock
+	next   int
+	pos    time.Duration
+	speed  int
+	paused bool
+	width  int
+}
+
+// NewPlayer returns a player at the start of the recording, at normal speed
+func NewPlayer(r *engine.Recording) *Player {
+	p := &Player{rec: r, speed: 1}
+	p.rewind()
+	return p
+}
+
+// rewind starts the run over
+func (p *Player) rewind() {
+	p.e, p.clock = newEngine(p.rec)
+	p.next = 0
+	p.pos = 0
+}
+
+// Seek moves the playback to pos. Going back plays the run again from the
+// start, the engine cannot undo calls.
+func (p *Player) Seek(pos time.Duration) {
+	pos = max(min(pos, p.rec.Duration()), 0)
+	if pos < p.pos {
+		p.rewind()
+	}
+	inputs := p.rec.Inputs
+	for p.next < len(inputs) && inputs[p.next].At <= pos {
+		p.clock.now = p.rec.Started.Add(inputs[p.next].At)
+		apply(p.e, inputs[p.next])
+		p.next++
+	}
+	p.pos = pos
+	// Keep timers running smoothly between recorded calls
+	p.clock.now = p.rec.Started.Add(pos)
+	p.e.M.Now = p.clock.now
+}
+
+func (p *Player) Init() tea.Cmd {
+	return frame()
+}
+
+func (p *Player) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+
+	case frameMsg:
+		if !p.paused {
+			p.Seek(p.pos + frameRate*time.Duration(p.speed))
+			if p.pos >= p.rec.Duration() {
+				p.paused = true
+			}
+		}
+		return p, frame()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return p, tea.Quit
+		case " ":
+			if p.paused && p.pos >= p.rec.Duration() {
+				p.Seek(0)
+			}
+			p.paused = !p.paused
+		case "left":
+			p.Seek(p.pos - seekStep)
+		case "right":
+			p.Seek(p.pos + seekStep)
+		case "home":
+			p.Seek(0)
+		case "end":
+			p.Seek(p.rec.Duration())
+		case "1", "2", "4":
+			p.speed = int(msg.Runes[0] - '0')
+		}
+	}
+	return p, nil
+}
+
+func (p *Player) View() string {
+	return ui.View(p.e.M) + "\n" + ui.ViewReplayBar(p.width, p.pos, p.rec.Duration(), p.speed, p.paused)
+}
+
+// Run plays the replay file at path in the terminal
+func Run(path string) error {
+	r, err := Load(path)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(NewPlayer(r)).Run()
+	return err
+}
@@ -0,0 +1,133 @@
+// Package replay saves the recordings of finished runs and plays them back
+// through the engine, with the same view as the game.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gokana/internal/engine"
+	"gokana/internal/model"
+	"gokana/internal/srs"
+	"gokana/internal/xdg"
+)
+
+// Dir returns the directory replays are saved to
+func Dir() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "replays"), nil
+}
+
+// Save writes a recording to the replay directory, named after the time the
+// run started, and returns its path
+func Save(r *engine.Recording) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, r.Started.Format("2006-01-02T15-04-05")+".json")
+	if err := xdg.WriteFile(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Load reads the replay file at path
+func Load(path string) (*engine.Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &engine.Recording{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if r.Width <= 0 || r.Height <= 0 {
+		return nil, fmt.Errorf("%s: %w", path, errors.New("missing play field size"))
+	}
+	return r, nil
+}
+
+// clock is moved by the playback to the time of each recorded call
+type clock struct {
+	now time.Time
+}
+
+// Now returns the time of the call being played
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// newEngine returns an engine at the start of the recorded run, with the
+// settings, kana pool and play field it had
+func newEngine(r *engine.Recording) (*engine.Engine, *clock) {
+	m := &model.Model{
+		State:         model.StateMenu,
+		FallingKanas:  []model.FallingKana{},
+		MaxFallHeight: r.Height,
+		PlayAreaWidth: r.Width,
+		TermWidth:     model.MinTerminalWidth,
+		TermHeight:    model.MinTerminalHeight,
+		Replaying:     true,
+	}
+	m.ApplySettings(r.Settings)
+	if r.Deck != nil {
+		// The deck is stored with the replay, it may have changed or be gone since
+		m.Decks = []model.Deck{*r.Deck}
+		m.SelectedDeck = 0
+	}
+	m.PracticeKana = r.Practice
+	m.Daily = r.Daily
+	if r.Cards != nil {
+		// Reviews during playback only touch this copy, never the scheduler file
+		cards := make(map[string]*srs.Card, len(r.Cards))
+		for character, c := range r.Cards {
+			card := *c
+			cards[character] = &card
+		}
+		m.Scheduler = &srs.Scheduler{Cards: cards}
+	}
+
+	c := &clock{now: r.Started}
+	e := engine.New(m, c, nil)
+	e.Start(r.Seed)
+	e.Recording = nil
+	return e, c
+}
+
+// apply makes a recorded call on the engine
+func apply(e *engine.Engine, in engine.Input) {
+	switch in.Op {
+	case engine.OpStep:
+		e.Step(in.Dt)
+	case engine.OpSubmit:
+		e.Submit(in.Text)
+	case engine.OpChoose:
+		e.Choose(in.Index)
+	case engine.OpBackspace:
+		e.Backspace()
+	case engine.OpSettle:
+		e.Settle()
+	case engine.OpClearFeedback:
+		e.ClearFeedback()
+	case engine.OpPause:
+		e.Pause()
+	case engine.OpResume:
+		e.Resume()
+	case engine.OpEnd:
+		e.End()
+	case engine.OpField:
+		e.SetField(in.Width, in.Height)
+	}
+}
@@ -0,0 +1,135 @@
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gokana/internal/engine"
+	"gokana/internal/model"
+)
+
+// play runs a game with the default settings changed by configure, making
+// every kind of call the game makes, and returns its engine
+func play(seed int64, configure func(s *model.Settings)) *engine.Engine {
+	s := model.DefaultSettings()
+	configure(&s)
+	m := &model.Model{
+		FallingKanas:  []model.FallingKana{},
+		PlayAreaWidth: 40,
+		MaxFallHeight: 10,
+	}
+	m.ApplySettings(s)
+
+	c := &clock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	e := engine.New(m, c, nil)
+	e.Start(seed)
+
+	const dt = 50 * time.Millisecond
+	for i := range 600 {
+		c.now = c.now.Add(dt)
+		switch {
+		case i == 200:
+			e.Pause()
+			c.now = c.now.Add(3 * time.Second)
+			e.Resume()
+		case i == 300:
+			e.SetField(30, 8)
+		case i%29 == 0:
+			e.Submit("q")
+		case i%31 == 0:
+			e.ClearFeedback()
+		case i%11 == 0 && m.Mode == model.ModeQuiz:
+			e.Choose(i % 4)
+		case i%11 == 0:
+			for _, fk := range m.FallingKanas {
+				if !fk.ShowingCorrect {
+					e.Submit(fk.Kana.Romaji)
+					break
+				}
+			}
+		case i%13 == 0:
+			e.Settle()
+		default:
+			e.Step(dt)
+		}
+		if m.GameOver {
+			return e
+		}
+	}
+	e.End()
+	return e
+}
+
+func TestPlayerSeekToEnd(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(s *model.Settings)
+	}{
+		{"classic", func(s *model.Settings) { s.StartLevel = 3 }},
+		{"words", func(s *model.Settings) { s.Mode = model.ModeWords; s.StartLevel = 5 }},
+		{"quiz", func(s *model.Settings) { s.Mode = model.ModeQuiz }},
+		{"game over", func(s *model.Settings) { s.StartLevel = 8; s.StartLives = 1 }},
+	}
+	for _, tt := range tests {
+		e := play(7, tt.configure)
+		want := e.M
+
+		data, err := json.Marshal(e.Recording)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r engine.Recording
+		if err := json.Unmarshal(data, &r); err != nil {
+			t.Fatal(err)
+		}
+		p := NewPlayer(&r)
+		p.Seek(r.Duration())
+		got := p.e.M
+
+		if got.Points != want.Points || got.Correct != want.Correct || got.Lives != want.Lives ||
+			got.WrongInputs != want.WrongInputs || got.State != want.State {
+			t.Errorf("%s: replay ended with %d points, %d correct, %d lives, %d wrong inputs, state %v; "+
+				"the run with %d points, %d correct, %d lives, %d wrong inputs, state %v",
+				tt.name, got.Points, got.Correct, got.Lives, got.WrongInputs, got.State,
+				want.Points, want.Correct, want.Lives, want.WrongInputs, want.State)
+		}
+		same := func(x, y model.FallingKana) bool {
+			return x.Kana.Character == y.Kana.Character && x.HorizontalPos == y.HorizontalPos &&
+				x.FallPosition == y.FallPosition && x.ShowingCorrect == y.ShowingCorrect
+		}
+		if !slices.EqualFunc(got.FallingKanas, want.FallingKanas, same) {
+			t.Errorf("%s: replay ended with\n%v\nthe run with\n%v", tt.name, got.FallingKanas, want.FallingKanas)
+		}
+		if got.QuizQuestion.Kana.Character != want.QuizQuestion.Kana.Character {
+			t.Errorf("%s: replay asks %s, the run %s", tt.name, got.QuizQuestion.Kana.Character, want.QuizQuestion.Kana.Character)
+		}
+		if want.Correct == 0 || want.Total == want.Correct {
+			t.Errorf("%s: the run had %d of %d answers right, the test needs right and wrong ones", tt.name, want.Correct, want.Total)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"syntax.json": `{"seed": 1,`,
+		"field.json":  `{"seed": 1, "inputs": []}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: error %v, want it to name the file", name, err)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("missing file: error %v, want not exist", err)
+	}
+}
@@ -0,0 +1,154 @@
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gokana/internal/xdg"
+)
+
+// Config identifies the settings a score was achieved with, only scores
+// sharing the same config are ranked against each other
+type Config struct {
+	Mode       string `json:"mode,omitempty"`
+	KanaSet    string `json:"kana_set"`
+	Dakuten    bool   `json:"dakuten"`
+	Yoon       bool   `json:"yoon"`
+	Rows       string `json:"rows,omitempty"`
+	StartLevel int    `json:"start_level"`
+	StartLives int    `json:"lives"`
+	TimeLimit  int    `json:"time_limit,omitempty"`
+	Daily      string `json:"daily,omitempty"`
+}
+
+func (c Config) String() string {
+	if c.Daily != "" {
+		return "Daily Challenge " + c.Daily
+	}
+	s := c.KanaSet
+	if c.Mode != "" {
+		s = c.Mode + " · " + s
+	}
+	if c.Dakuten {
+		s += " + dakuten"
+	}
+	if c.Yoon {
+		s += " + yōon"
+	}
+	if c.Rows != "" {
+		s += " · rows " + c.Rows
+	}
+	if c.StartLevel > 1 {
+		s += fmt.Sprintf(" · from level %d", c.StartLevel)
+	}
+	if c.StartLives > 0 {
+		s += fmt.Sprintf(" · %d lives", c.StartLives)
+	}
+	if c.TimeLimit > 0 {
+		s += fmt.Sprintf(" · %ds", c.TimeLimit)
+	}
+	return s
+}
+
+// Score is a finished run
+type Score struct {
+	Config
+	Points  int       `json:"points"`
+	Correct int       `json:"correct"`
+	Level   int       `json:"level"`
+	Date    time.Time `json:"date"`
+}
+
+// Board holds every recorded score
+type Board struct {
+	Scores []Score `json:"scores"`
+	path   string
+}
+
+// Path returns the location of the high score file
+func Path() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scores.json"), nil
+}
+
+// Load reads the high score file at path. A missing file yields an empty board.
+func Load(path string) (*Board, error) {
+	b := &Board{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save writes the board back to the file it was loaded from
+func (b *Board) Save() error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(b.path, data)
+}
+
+// Add records a score and returns its 1-based rank among scores of the same config
+func (b *Board) Add(s Score) int {
+	b.Scores = append(b.Scores, s)
+	rank := 1
+	for _, other := range b.Scores {
+		if other.Config == s.Config && other.Points > s.Points {
+			rank++
+		}
+	}
+	return rank
+}
+
+// Top returns up to n best scores for the config, highest first, older first on ties
+func (b *Board) Top(c Config, n int) []Score {
+	var top []Score
+	for _, s := range b.Scores {
+		if s.Config == c {
+			top = append(top, s)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Points != top[j].Points {
+			return top[i].Points > top[j].Points
+		}
+		return top[i].Date.Before(top[j].Date)
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Configs returns every config with at least one score, in a stable order
+func (b *Board) Configs() []Config {
+	seen := make(map[Config]bool)
+	var configs []Config
+	for _, s := range b.Scores {
+		if !seen[s.Config] {
+			seen[s.Config] = true
+			configs = append(configs, s.Config)
+		}
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return configs[i].String() < configs[j].String()
+	})
+	return configs
+}
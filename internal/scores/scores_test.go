@@ -0,0 +1,54 @@
+package scores
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gokana", "scores.json")
+	b, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Scores) != 0 {
+		t.Fatalf("a missing file gave %v, want no scores", b.Scores)
+	}
+
+	classic := Config{Mode: "Classic", KanaSet: "Hiragana", StartLevel: 1, StartLives: 3}
+	timed := Config{Mode: "Classic", KanaSet: "Hiragana", StartLevel: 1, TimeLimit: 60}
+	date := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b.Add(Score{Config: classic, Points: 100, Correct: 10, Level: 1, Date: date})
+	b.Add(Score{Config: timed, Points: 300, Correct: 30, Level: 2, Date: date})
+	if rank := b.Add(Score{Config: classic, Points: 200, Correct: 20, Level: 2, Date: date.Add(time.Hour)}); rank != 1 {
+		t.Errorf("the best classic score ranked %d, want 1", rank)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top := loaded.Top(classic, 10)
+	if len(top) != 2 || top[0].Points != 200 || top[1].Points != 100 || !top[0].Date.Equal(date.Add(time.Hour)) {
+		t.Errorf("classic scores = %+v after loading, want 200 then 100", top)
+	}
+	if configs := loaded.Configs(); len(configs) != 2 {
+		t.Errorf("configs = %v after loading, want classic and timed", configs)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	if err := os.WriteFile(path, []byte(`{"scores": [{"points": "many"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("error %v, want it to name the file", err)
+	}
+}
@@ -0,0 +1,119 @@
+package srs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gokana/internal/xdg"
+)
+
+// MaxBox is the highest Leitner box a card can reach
+const MaxBox = 5
+
+// intervals is the time a card waits before being due again, per box
+var intervals = [MaxBox + 1]time.Duration{
+	0,
+	0,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+}
+
+// Card holds the Leitner state of a single character
+type Card struct {
+	Box        int       `json:"box"`
+	Due        time.Time `json:"due"`
+	LastReview time.Time `json:"last_review"`
+}
+
+// Scheduler tracks the strength of every character across sessions
+type Scheduler struct {
+	Cards map[string]*Card `json:"cards"`
+	path  string
+}
+
+// Path returns the location of the scheduler file
+func Path() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "srs.json"), nil
+}
+
+// Load reads the scheduler file at path. A missing file yields an empty scheduler.
+func Load(path string) (*Scheduler, error) {
+	s := &Scheduler{Cards: make(map[string]*Card), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Cards == nil {
+		s.Cards = make(map[string]*Card)
+	}
+	for character, c := range s.Cards {
+		if c == nil {
+			delete(s.Cards, character)
+			continue
+		}
+		c.Box = max(1, min(c.Box, MaxBox))
+	}
+	return s, nil
+}
+
+// Save writes the scheduler back to the file it was loaded from
+func (s *Scheduler) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(s.path, data)
+}
+
+// Review moves a card up one box on a correct answer, or back to the first
+// box on a mistake, and schedules its next due date
+func (s *Scheduler) Review(character string, correct bool, now time.Time) {
+	c, ok := s.Cards[character]
+	if !ok {
+		c = &Card{Box: 1}
+		s.Cards[character] = c
+	}
+
+	if correct {
+		if c.Box < MaxBox {
+			c.Box++
+		}
+	} else {
+		c.Box = 1
+	}
+	c.LastReview = now
+	c.Due = now.Add(intervals[c.Box])
+}
+
+// Weight returns the relative spawn weight of a character. Cards in lower
+// boxes weigh more, and due cards weigh twice as much as cards not yet due.
+// Characters never reviewed weigh as much as a due card in the first box.
+func (s *Scheduler) Weight(character string, now time.Time) float64 {
+	c, ok := s.Cards[character]
+	if !ok {
+		return float64(int(1)<<(MaxBox-1)) * 2
+	}
+
+	weight := float64(int(1) << (MaxBox - c.Box))
+	if !now.Before(c.Due) {
+		weight *= 2
+	}
+	return weight
+}
@@ -0,0 +1,76 @@
+package srs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gokana", "srs.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Cards) != 0 {
+		t.Fatalf("a missing file gave %v, want no cards", s.Cards)
+	}
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.Review("あ", true, now)
+	s.Review("あ", true, now)
+	s.Review("い", false, now)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Cards) != 2 {
+		t.Fatalf("loaded %d cards, saved 2", len(loaded.Cards))
+	}
+	for character, want := range s.Cards {
+		got := loaded.Cards[character]
+		if got == nil || got.Box != want.Box || !got.Due.Equal(want.Due) || !got.LastReview.Equal(want.LastReview) {
+			t.Errorf("%s = %+v after loading, saved %+v", character, got, want)
+		}
+	}
+	if got := loaded.Weight("あ", now); got != 4 {
+		t.Errorf("weight of a box 3 card not due = %v, want 4", got)
+	}
+}
+
+func TestLoadRepairsCards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "srs.json")
+	content := `{"cards": {"あ": {"box": 9}, "い": {"box": 0}, "う": null}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Cards["あ"].Box; got != MaxBox {
+		t.Errorf("box 9 loaded as %d, want %d", got, MaxBox)
+	}
+	if got := s.Cards["い"].Box; got != 1 {
+		t.Errorf("box 0 loaded as %d, want 1", got)
+	}
+	if _, ok := s.Cards["う"]; ok {
+		t.Error("a null card was kept")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "srs.json")
+	if err := os.WriteFile(path, []byte(`{"cards": [`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("error %v, want it to name the file", err)
+	}
+}
@@ -0,0 +1,127 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gokana/internal/xdg"
+)
+
+// Entry holds the statistics recorded for a single character
+type Entry struct {
+	Attempts     int   `json:"attempts"`
+	Correct      int   `json:"correct"`
+	Misses       int   `json:"misses"`
+	WrongAnswers int   `json:"wrong_answers"`
+	WrongPrefix  int   `json:"wrong_prefix"`
+	AnswerTime   int64 `json:"answer_time_ms"`
+}
+
+// AverageAnswerTime returns the mean time between a kana spawning and being answered
+func (e Entry) AverageAnswerTime() time.Duration {
+	if e.Correct == 0 {
+		return 0
+	}
+	return time.Duration(e.AnswerTime/int64(e.Correct)) * time.Millisecond
+}
+
+// Accuracy returns the share of attempts answered correctly, between 0 and 1
+func (e Entry) Accuracy() float64 {
+	if e.Attempts == 0 {
+		return 0
+	}
+	return float64(e.Correct) / float64(e.Attempts)
+}
+
+// Store holds per-character statistics across sessions
+type Store struct {
+	Kana map[string]*Entry `json:"kana"`
+	path string
+}
+
+// Path returns the location of the statistics file
+func Path() (string, error) {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stats.json"), nil
+}
+
+// Load reads the statistics file at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{Kana: make(map[string]*Entry), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Kana == nil {
+		s.Kana = make(map[string]*Entry)
+	}
+	return s, nil
+}
+
+// Save writes the statistics back to the file they were loaded from
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(s.path, data)
+}
+
+// Get returns the statistics of a character, zero if it was never seen
+func (s *Store) Get(character string) Entry {
+	if e, ok := s.Kana[character]; ok {
+		return *e
+	}
+	return Entry{}
+}
+
+func (s *Store) entry(character string) *Entry {
+	e, ok := s.Kana[character]
+	if !ok {
+		e = &Entry{}
+		s.Kana[character] = e
+	}
+	return e
+}
+
+// RecordCorrect records a correct answer given after the kana was on screen for elapsed
+func (s *Store) RecordCorrect(character string, elapsed time.Duration) {
+	e := s.entry(character)
+	e.Attempts++
+	e.Correct++
+	e.AnswerTime += elapsed.Milliseconds()
+}
+
+// RecordMiss records a kana that fell to the bottom without being answered
+func (s *Store) RecordMiss(character string) {
+	e := s.entry(character)
+	e.Attempts++
+	e.Misses++
+}
+
+// RecordWrongAnswer records a wrong pick in the multiple-choice quiz
+func (s *Store) RecordWrongAnswer(character string) {
+	e := s.entry(character)
+	e.Attempts++
+	e.WrongAnswers++
+}
+
+// RecordWrongPrefix records input that could not lead to the kana's answer
+func (s *Store) RecordWrongPrefix(character string) {
+	e := s.entry(character)
+	e.WrongPrefix++
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	// The data directory is created on the first save
+	path := filepath.Join(t.TempDir(), "gokana", "stats.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Kana) != 0 {
+		t.Fatalf("a missing file gave %v, want no statistics", s.Kana)
+	}
+
+	s.RecordCorrect("あ", 1500*time.Millisecond)
+	s.RecordCorrect("あ", 500*time.Millisecond)
+	s.RecordMiss("あ")
+	s.RecordWrongAnswer("い")
+	s.RecordWrongPrefix("い")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, character := range []string{"あ", "い"} {
+		if got, want := loaded.Get(character), s.Get(character); got != want {
+			t.Errorf("%s = %+v after loading, saved %+v", character, got, want)
+		}
+	}
+	if got := loaded.Get("あ").AverageAnswerTime(); got != time.Second {
+		t.Errorf("average answer time = %v, want 1s", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, content string
+	}{
+		{"syntax.json", `{"kana": {"あ": `},
+		{"type.json", `{"kana": {"あ": {"attempts": "many"}}}`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: error %v, want it to name the file", tt.name, err)
+		}
+	}
+
+	// A file without statistics is an empty store, not a nil map
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Kana == nil {
+		t.Error("a file without statistics loaded a nil map")
+	}
+}
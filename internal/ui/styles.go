@@ -14,9 +14,7 @@ var (
 
 	PlayAreaStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
-		Width(60).
-		Height(12)
+		BorderForeground(lipgloss.Color("63"))
 
 	InputStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240"))
@@ -2,14 +2,22 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"gokana/internal/ime"
 	"gokana/internal/model"
+	"gokana/internal/scores"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func View(m *model.Model) string {
+	if m.TerminalTooSmall && !m.Quitting {
+		return viewTooSmall(m)
+	}
 	if m.Quitting {
 		points := m.GetPoints()
 		if m.GameOver {
@@ -18,27 +26,46 @@ func View(m *model.Model) string {
 		return fmt.Sprintf("\nFinal Score: %d points (%d correct)\n", points, m.Correct)
 	}
 
+	var view string
 	switch m.State {
 	case model.StateMenu:
-		return viewMenu(m)
-	case model.StatePlaying:
-		return viewGame(m)
-	default:
-		return ""
+		if m.PickingRows {
+			view = viewRowPicker(m)
+		} else {
+			view = viewMenu(m)
+		}
+	case model.StatePlaying, model.StatePaused:
+		view = viewGame(m)
+	case model.StateGameOver:
+		view = viewGameOver(m)
+	case model.StateLeaderboard:
+		view = viewLeaderboard(m)
 	}
+	return fitWidth(view, m.TermWidth)
+}
+
+// fitWidth cuts lines longer than the terminal, such as a long deck
+// description, so they never wrap and push the layout past the screen
+func fitWidth(view string, width int) string {
+	if width <= 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	return strings.Join(lines, "\n")
 }
 
 func viewMenu(m *model.Model) string {
 	var s strings.Builder
 
-	s.WriteString(TitleStyle.Render("🗾 Gokana"))
-	s.WriteString("\n\n")
-
+	// The menu keeps to one line per setting so it fits the minimum terminal height
 	subtitle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("111")).
 		Italic(true).
 		Render("Japanese Kana Quiz Game")
-	s.WriteString(subtitle)
+	s.WriteString(TitleStyle.UnsetMarginBottom().Render("🗾 Gokana") + "  " + subtitle)
 	s.WriteString("\n\n")
 
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
@@ -47,45 +74,49 @@ func viewMenu(m *model.Model) string {
 	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
 	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
-	// Kana Selection
-	kanaHeader := "Character Set:"
-	if m.MenuSection == model.MenuSectionKana {
-		s.WriteString(activeSectionStyle.Render("▸ " + kanaHeader))
+	// Mode Selection
+	modeHeader := "Mode:"
+	if m.MenuSection == model.MenuSectionMode {
+		s.WriteString(activeSectionStyle.Render("▸ " + modeHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", m.Mode.String())))
 	} else {
-		s.WriteString(sectionStyle.Render("  " + kanaHeader))
+		s.WriteString(sectionStyle.Render("  " + modeHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(m.Mode.String()))
 	}
+	s.WriteString("  " + dimStyle.Render(m.Mode.Description()))
 	s.WriteString("\n")
 
-	options := []struct {
-		name string
-		desc string
-	}{
-		{"Hiragana", "あ い う え お"},
-		{"Katakana", "ア イ ウ エ オ"},
-		{"Both", "あ ア い イ う ウ"},
-	}
-
-	for i, opt := range options {
-		cursor := "    "
-		var optStyle lipgloss.Style
-		if m.MenuSection == model.MenuSectionKana {
-			if i == m.MenuCursor {
-				cursor = "  ▸ "
-				optStyle = activeValueStyle
-			} else {
-				optStyle = dimStyle
-			}
-		} else {
-			if i == int(m.SelectedKana) {
-				cursor = "  ✓ "
-				optStyle = valueStyle
-			} else {
-				optStyle = dimStyle
+	// Kana Selection
+	kanaHeader := "Character Set:"
+	kanaName, kanaDesc := m.SetName(), ""
+	switch m.SelectedKana {
+	case model.KanaTypeHiragana:
+		kanaDesc = "あ い う え お"
+	case model.KanaTypeKatakana:
+		kanaDesc = "ア イ ウ エ オ"
+	case model.KanaTypeBoth:
+		kanaDesc = "あ ア い イ う ウ"
+	case model.KanaTypeDeck:
+		if m.SelectedDeck >= 0 && m.SelectedDeck < len(m.Decks) {
+			d := m.Decks[m.SelectedDeck]
+			kanaDesc = d.Description
+			if kanaDesc == "" {
+				kanaDesc = fmt.Sprintf("%d cards", len(d.Kana))
 			}
 		}
-		line := cursor + optStyle.Render(opt.name) + "  " + dimStyle.Render(opt.desc)
-		s.WriteString(line + "\n")
 	}
+	if m.MenuSection == model.MenuSectionKana {
+		s.WriteString(activeSectionStyle.Render("▸ " + kanaHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", kanaName)))
+	} else {
+		s.WriteString(sectionStyle.Render("  " + kanaHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(kanaName))
+	}
+	s.WriteString("  " + dimStyle.Render(kanaDesc))
 	s.WriteString("\n")
 
 	// Dakuten Selection
@@ -111,7 +142,84 @@ func viewMenu(m *model.Model) string {
 			s.WriteString("  " + dimStyle.Render("basic kana only"))
 		}
 	}
-	s.WriteString("\n\n")
+	s.WriteString("\n")
+
+	// Yōon Selection
+	yoonHeader := "Include Yōon:"
+	if m.MenuSection == model.MenuSectionYoon {
+		s.WriteString(activeSectionStyle.Render("▸ " + yoonHeader))
+		s.WriteString("  ")
+		if m.YoonEnabled {
+			s.WriteString(activeValueStyle.Render("< ON >"))
+			s.WriteString("  " + dimStyle.Render("きゃ しゅ ちょ"))
+		} else {
+			s.WriteString(activeValueStyle.Render("< OFF >"))
+			s.WriteString("  " + dimStyle.Render("single kana only"))
+		}
+	} else {
+		s.WriteString(sectionStyle.Render("  " + yoonHeader))
+		s.WriteString("  ")
+		if m.YoonEnabled {
+			s.WriteString(valueStyle.Render("ON"))
+			s.WriteString("  " + dimStyle.Render("きゃ しゅ ちょ"))
+		} else {
+			s.WriteString(valueStyle.Render("OFF"))
+			s.WriteString("  " + dimStyle.Render("single kana only"))
+		}
+	}
+	s.WriteString("\n")
+
+	// Row Selection
+	rowsHeader := "Gojūon Rows:"
+	if m.MenuSection == model.MenuSectionRows {
+		s.WriteString(activeSectionStyle.Render("▸ " + rowsHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(rowSummary(m)))
+		s.WriteString("  " + dimStyle.Render("Enter to pick rows"))
+	} else {
+		s.WriteString(sectionStyle.Render("  " + rowsHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(rowSummary(m)))
+	}
+	s.WriteString("\n")
+
+	// Romanization Selection
+	romanizationHeader := "Romanization:"
+	romanizationDesc := map[model.RomanizationSystem]string{
+		model.RomanizationAny:     "shi, si, tsu, tu... all accepted",
+		model.RomanizationHepburn: "shi chi tsu fu ji",
+		model.RomanizationKunrei:  "si ti tu hu zi",
+		model.RomanizationNihon:   "si ti tu hu zi di du wo",
+	}[m.Romanization]
+	if m.MenuSection == model.MenuSectionRomanization {
+		s.WriteString(activeSectionStyle.Render("▸ " + romanizationHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", m.Romanization.String())))
+	} else {
+		s.WriteString(sectionStyle.Render("  " + romanizationHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(m.Romanization.String()))
+	}
+	s.WriteString("  " + dimStyle.Render(romanizationDesc))
+	s.WriteString("\n")
+
+	// Spawn Mode Selection
+	spawnHeader := "Spawning:"
+	spawnDesc := "every kana equally likely"
+	if m.SpawnMode == model.SpawnAdaptive {
+		spawnDesc = "weak and due kana more often"
+	}
+	if m.MenuSection == model.MenuSectionSpawn {
+		s.WriteString(activeSectionStyle.Render("▸ " + spawnHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", m.SpawnMode.String())))
+	} else {
+		s.WriteString(sectionStyle.Render("  " + spawnHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(m.SpawnMode.String()))
+	}
+	s.WriteString("  " + dimStyle.Render(spawnDesc))
+	s.WriteString("\n")
 
 	// Level Selection
 	levelHeader := "Starting Level:"
@@ -124,11 +232,66 @@ func viewMenu(m *model.Model) string {
 		s.WriteString("  ")
 		s.WriteString(valueStyle.Render(fmt.Sprintf("%d", m.StartLevel)))
 	}
-	s.WriteString("\n\n")
+	s.WriteString("\n")
+
+	// Time Attack Selection
+	timeAttackHeader := "Time Attack:"
+	timeAttackDesc := "play until out of lives"
+	if m.Timed() {
+		timeAttackDesc = "no lives, score as much as you can"
+	}
+	if m.MenuSection == model.MenuSectionTimeAttack {
+		s.WriteString(activeSectionStyle.Render("▸ " + timeAttackHeader))
+		s.WriteString("  ")
+		s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", m.TimeAttack.String())))
+	} else {
+		s.WriteString(sectionStyle.Render("  " + timeAttackHeader))
+		s.WriteString("  ")
+		s.WriteString(valueStyle.Render(m.TimeAttack.String()))
+	}
+	s.WriteString("  " + dimStyle.Render(timeAttackDesc))
+	s.WriteString("\n")
+
+	// Zen Selection
+	zenHeader := "Zen Mode:"
+	if m.MenuSection == model.MenuSectionZen {
+		s.WriteString(activeSectionStyle.Render("▸ " + zenHeader))
+		s.WriteString("  ")
+		if m.Zen {
+			s.WriteString(activeValueStyle.Render("< ON >"))
+		} else {
+			s.WriteString(activeValueStyle.Render("< OFF >"))
+		}
+	} else {
+		s.WriteString(sectionStyle.Render("  " + zenHeader))
+		s.WriteString("  ")
+		if m.Zen {
+			s.WriteString(valueStyle.Render("ON"))
+		} else {
+			s.WriteString(valueStyle.Render("OFF"))
+		}
+	}
+	if m.Zen {
+		s.WriteString("  " + dimStyle.Render("no lives, no speed-up, misses come back"))
+	} else {
+		s.WriteString("  " + dimStyle.Render("regular lives and speed-up"))
+	}
+	s.WriteString("\n")
 
 	// Lives Selection
 	livesHeader := "Starting Lives:"
-	if m.MenuSection == model.MenuSectionLives {
+	if !m.LivesEnabled() {
+		if m.MenuSection == model.MenuSectionLives {
+			s.WriteString(activeSectionStyle.Render("▸ " + livesHeader))
+		} else {
+			s.WriteString(sectionStyle.Render("  " + livesHeader))
+		}
+		if m.Zen {
+			s.WriteString("  " + dimStyle.Render("disabled in zen mode"))
+		} else {
+			s.WriteString("  " + dimStyle.Render("disabled in time attack"))
+		}
+	} else if m.MenuSection == model.MenuSectionLives {
 		s.WriteString(activeSectionStyle.Render("▸ " + livesHeader))
 		s.WriteString("  ")
 		hearts := ""
@@ -147,7 +310,9 @@ func viewMenu(m *model.Model) string {
 		s.WriteString(valueStyle.Render(fmt.Sprintf("%d", m.StartLives)))
 		s.WriteString("  " + hearts)
 	}
-	s.WriteString("\n\n")
+	s.WriteString("\n")
+
+	s.WriteString("\n")
 
 	// Start Button
 	if m.MenuSection == model.MenuSectionStart {
@@ -163,9 +328,131 @@ func viewMenu(m *model.Model) string {
 			Padding(0, 2)
 		s.WriteString(startBtnStyle.Render("  START GAME"))
 	}
+	s.WriteString("  ")
+
+	// Daily Challenge Button
+	if m.MenuSection == model.MenuSectionDaily {
+		dailyBtnStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("42")).
+			Padding(0, 2)
+		s.WriteString(dailyBtnStyle.Render("▸ DAILY CHALLENGE"))
+	} else {
+		dailyBtnStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Padding(0, 2)
+		s.WriteString(dailyBtnStyle.Render("  DAILY CHALLENGE"))
+	}
+	s.WriteString("  ")
+
+	// High Scores Button
+	if m.MenuSection == model.MenuSectionScores {
+		scoresBtnStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("111")).
+			Padding(0, 2)
+		s.WriteString(scoresBtnStyle.Render("▸ HIGH SCORES"))
+	} else {
+		scoresBtnStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Padding(0, 2)
+		s.WriteString(scoresBtnStyle.Render("  HIGH SCORES"))
+	}
 	s.WriteString("\n\n")
 
-	helpText := dimStyle.Render("←/→ sections • ↑/↓ adjust • Enter to confirm • ESC to quit")
+	if m.MenuSection == model.MenuSectionDaily {
+		today := model.DailyDate(time.Now())
+		daily := "Same kana for everyone today • Classic, all kana, 4 lives"
+		if m.Scores != nil {
+			if best := m.Scores.Top(model.DailyScoreConfig(today), 1); len(best) > 0 {
+				daily += fmt.Sprintf(" • best today: %d", best[0].Points)
+			}
+		}
+		s.WriteString(dimStyle.Render(ansi.Wrap(daily, m.TermWidth, "")))
+		s.WriteString("\n\n")
+	}
+
+	helpText := dimStyle.Render("←/→ section • ↑/↓ adjust • Enter • r reset • ESC quit")
+	s.WriteString(helpText)
+
+	if len(m.DeckErrors) > 0 {
+		deckErr := m.DeckErrors[0]
+		if len(m.DeckErrors) > 1 {
+			deckErr += fmt.Sprintf(" (and %d more, run gokana decks)", len(m.DeckErrors)-1)
+		}
+		s.WriteString("\n" + WrongStyle.Render("⚠ ") + dimStyle.Render(deckErr))
+	}
+	if m.StatsError != "" {
+		s.WriteString("\n" + WrongStyle.Render("⚠ ") + dimStyle.Render("statistics: "+m.StatsError))
+	}
+	if m.SchedulerError != "" {
+		s.WriteString("\n" + WrongStyle.Render("⚠ ") + dimStyle.Render("spaced repetition: "+m.SchedulerError))
+	}
+	if m.SettingsError != "" {
+		s.WriteString("\n" + WrongStyle.Render("⚠ ") + dimStyle.Render("settings: "+m.SettingsError))
+	}
+
+	return s.String()
+}
+
+func rowSummary(m *model.Model) string {
+	var rows []string
+	for r := model.KanaRow(0); r < model.KanaRowCount; r++ {
+		if m.SelectedRows[r] {
+			rows = append(rows, strings.Fields(r.Label())[0])
+		}
+	}
+	if len(rows) == model.KanaRowCount {
+		return "All"
+	}
+	return strings.Join(rows, " ")
+}
+
+func viewRowPicker(m *model.Model) string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render("🗾 Gojūon Rows"))
+	s.WriteString("\n\n")
+
+	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("111"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	for r := model.KanaRow(0); r < model.KanaRowCount; r++ {
+		cursor := "  "
+		if int(r) == m.RowCursor {
+			cursor = "▸ "
+		}
+		check := "[ ]"
+		if m.SelectedRows[r] {
+			check = "[x]"
+		}
+
+		var chars []string
+		for _, k := range model.GetKanaSet(model.KanaTypeHiragana, true, false, map[model.KanaRow]bool{r: true}) {
+			chars = append(chars, k.Character)
+		}
+		sample := strings.Join(chars, " ")
+		if r.IsDakuten() && !m.DakutenEnabled {
+			sample += "  (needs dakuten)"
+		}
+
+		var optStyle lipgloss.Style
+		switch {
+		case int(r) == m.RowCursor:
+			optStyle = activeValueStyle
+		case m.SelectedRows[r]:
+			optStyle = valueStyle
+		default:
+			optStyle = dimStyle
+		}
+		s.WriteString(cursor + optStyle.Render(check+" "+r.Label()) + "  " + dimStyle.Render(sample) + "\n")
+	}
+	s.WriteString("\n")
+
+	helpText := dimStyle.Render("↑/↓ move • Space toggle • a all • n none • Enter/ESC back")
 	s.WriteString(helpText)
 
 	return s.String()
@@ -174,7 +461,10 @@ func viewMenu(m *model.Model) string {
 func viewGame(m *model.Model) string {
 	var s strings.Builder
 
-	title := fmt.Sprintf("🗾 %s Quiz", m.SelectedKana.String())
+	title := fmt.Sprintf("🗾 %s Quiz", m.SetName())
+	if m.Daily != "" {
+		title = "📅 Daily Challenge " + m.Daily
+	}
 	s.WriteString(TitleStyle.Render(title))
 	s.WriteString("\n\n")
 
@@ -191,10 +481,26 @@ func viewGame(m *model.Model) string {
 
 	points := m.GetPoints()
 	scoreText := fmt.Sprintf("⭐ %dpt", points)
+	streakText := fmt.Sprintf("🔥 %d ×%d", m.Streak, m.Multiplier())
 
-	statsLine := livesText + "  " + levelText + "  " + scoreText
+	if m.Zen {
+		livesText = "🧘 Zen"
+	}
+	if m.Timed() {
+		remaining := m.Remaining().Round(time.Second)
+		livesText = "⏱  " + minutesSeconds(remaining)
+	}
+
+	statsLine := livesText + "  " + levelText + "  " + scoreText + "  " + streakText
+	if m.Mode == model.ModeQuiz {
+		// The quiz has no lives and no levels, only the running tally
+		statsLine = fmt.Sprintf("✅ %d/%d", m.Correct, m.Total) + "  " + scoreText + "  " + streakText
+		if m.Timed() {
+			statsLine = livesText + "  " + statsLine
+		}
+	}
 	centeredStats := lipgloss.NewStyle().
-		Width(60).
+		Width(contentWidth(m)).
 		Align(lipgloss.Center).
 		Foreground(lipgloss.Color("111")).
 		Render(statsLine)
@@ -202,45 +508,35 @@ func viewGame(m *model.Model) string {
 	s.WriteString(centeredStats)
 	s.WriteString("\n\n")
 
-	var playArea strings.Builder
-	for row := 0; row < m.MaxFallHeight; row++ {
-		positionedKanas := make(map[int]string)
-		maxPos := 0
-
-		for _, fk := range m.FallingKanas {
-			if fk.FallPosition == row {
-				var kana string
-				if fk.ShowingCorrect {
-					correctKanaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
-					kana = correctKanaStyle.Render(fk.Kana.Character)
-				} else {
-					kana = KanaStyle.Render(fk.Kana.Character)
-				}
-				positionedKanas[fk.HorizontalPos] = kana
-				if fk.HorizontalPos > maxPos {
-					maxPos = fk.HorizontalPos
-				}
-			}
-		}
+	switch {
+	case m.State == model.StatePaused:
+		s.WriteString(playAreaStyle(m).Render(renderPauseOverlay(m)))
+	case m.Mode == model.ModeQuiz:
+		s.WriteString(playAreaStyle(m).Render(renderQuiz(m)))
+	default:
+		s.WriteString(playAreaStyle(m).Render(renderPlayArea(m)))
+	}
+	s.WriteString("\n\n")
 
-		line := ""
-		for pos := 0; pos < m.PlayAreaWidth; pos++ {
-			if kana, exists := positionedKanas[pos]; exists {
-				line += kana
-			} else {
-				line += " "
-			}
-		}
+	if m.Mode == model.ModeQuiz {
+		footer := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Press 1-4 to answer • Tab or Ctrl+P to pause • ESC to finish")
+		s.WriteString(footer)
+		return s.String()
+	}
 
-		playArea.WriteString(line)
-		if row < m.MaxFallHeight-1 {
-			playArea.WriteString("\n")
+	if m.Mode == model.ModeReverse && m.State == model.StatePlaying {
+		var choices []string
+		for i, c := range m.Candidates {
+			choices = append(choices, fmt.Sprintf("%s %s", lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(fmt.Sprintf("%d", i+1)), KanaStyle.Render(c.Character)))
 		}
+		centeredChoices := lipgloss.NewStyle().
+			Width(contentWidth(m)).
+			Align(lipgloss.Center).
+			Render(strings.Join(choices, "   "))
+		s.WriteString(centeredChoices)
+		s.WriteString("\n\n")
 	}
 
-	s.WriteString(PlayAreaStyle.Render(playArea.String()))
-	s.WriteString("\n\n")
-
 	borderColor := lipgloss.Color("240")
 	hasShowingCorrect := m.HasShowingCorrect()
 	if hasShowingCorrect {
@@ -257,21 +553,464 @@ func viewGame(m *model.Model) string {
 		Padding(0, 1)
 
 	inputDisplay := m.Input
+	if m.Mode == model.ModeTyping {
+		inputDisplay = composedInput(m)
+	}
 	if m.Input == "" {
 		inputDisplay = "_"
 	}
 
 	centeredInputBox := lipgloss.NewStyle().
-		Width(60).
+		Width(contentWidth(m)).
 		Align(lipgloss.Center).
 		Render(inputBoxStyle.Render(inputDisplay))
 
 	s.WriteString(centeredInputBox)
+	s.WriteString("\n")
+
+	if m.ShowingFeedback && m.Feedback != "" {
+		feedback := lipgloss.NewStyle().
+			Width(contentWidth(m)).
+			Align(lipgloss.Center).
+			Render(WrongStyle.Render(m.Feedback))
+		s.WriteString(feedback)
+	}
 	s.WriteString("\n\n")
 
-	helpText := "Press ESC or Ctrl+C to quit"
+	if m.Replaying {
+		// Playback has its own controls below the view
+		return s.String()
+	}
+
+	helpText := "Tab or Ctrl+P to pause • ESC or Ctrl+C to quit"
+	if m.Zen {
+		helpText = "Tab or Ctrl+P to pause • ESC to finish"
+	}
+	if m.Mode == model.ModeReverse {
+		helpText = "Type or press 1-9 • Tab/Ctrl+P pause • ESC/Ctrl+C quit"
+	}
 	footer := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(helpText)
 	s.WriteString(footer)
 
 	return s.String()
 }
+
+// contentWidth is the width of the play area content
+func contentWidth(m *model.Model) int {
+	return m.PlayAreaWidth
+}
+
+// playAreaStyle sizes the play area box to the current play field
+func playAreaStyle(m *model.Model) lipgloss.Style {
+	return PlayAreaStyle.
+		Width(contentWidth(m)).
+		Height(m.MaxFallHeight)
+}
+
+func viewTooSmall(m *model.Model) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	minWidth, minHeight := m.MinTerminalSize()
+	message := WrongStyle.Render("Terminal too small") + "\n\n" +
+		dimStyle.Render(fmt.Sprintf("%d×%d, need at least %d×%d", m.TermWidth, m.TermHeight, minWidth, minHeight)) + "\n" +
+		dimStyle.Render("Resize to continue • ESC to quit")
+	return lipgloss.Place(m.TermWidth, m.TermHeight, lipgloss.Center, lipgloss.Center, message)
+}
+
+// composedInput renders the typed romaji as live-composed kana, in katakana
+// when the input is heading for a falling katakana
+func composedInput(m *model.Model) string {
+	kana, pending := ime.Compose(m.Input)
+	for _, fk := range m.FallingKanas {
+		if ime.IsKatakana(fk.Kana.Character) && m.HasAnswerPrefix(fk.Kana, m.Input) {
+			kana = ime.ToKatakana(kana)
+			break
+		}
+	}
+	return kana + InputStyle.Render(pending)
+}
+
+// renderPlayArea draws the falling kana at their positions. Positions are
+// terminal cells, so double-width kana advance the line by their display width.
+func renderPlayArea(m *model.Model) string {
+	var playArea strings.Builder
+	for row := 0; row < m.MaxFallHeight; row++ {
+		var rowKanas []model.FallingKana
+		for _, fk := range m.FallingKanas {
+			if fk.FallPosition == row {
+				rowKanas = append(rowKanas, fk)
+			}
+		}
+		sort.Slice(rowKanas, func(i, j int) bool {
+			return rowKanas[i].HorizontalPos < rowKanas[j].HorizontalPos
+		})
+
+		positions := rowPositions(m, rowKanas)
+		var line strings.Builder
+		cursor := 0
+		for i, fk := range rowKanas {
+			pos := positions[i]
+			var kana string
+			if fk.ShowingCorrect {
+				correctKanaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+				kana = correctKanaStyle.Render(m.Label(fk.Kana))
+			} else {
+				kana = KanaStyle.Render(m.Label(fk.Kana))
+			}
+			line.WriteString(strings.Repeat(" ", pos-cursor))
+			line.WriteString(kana)
+			cursor = pos + m.LabelWidth(fk.Kana)
+		}
+		line.WriteString(strings.Repeat(" ", max(m.PlayAreaWidth-cursor, 0)))
+
+		playArea.WriteString(line.String())
+		if row < m.MaxFallHeight-1 {
+			playArea.WriteString("\n")
+		}
+	}
+	return playArea.String()
+}
+
+// rowPositions returns the cells the kana of a row, sorted by position, are
+// drawn at. Kana that would overlap, after the field shrank, are pushed
+// aside rather than hidden: a hidden kana would still cost a life.
+func rowPositions(m *model.Model, rowKanas []model.FallingKana) []int {
+	positions := make([]int, len(rowKanas))
+	limit := m.PlayAreaWidth
+	for i := len(rowKanas) - 1; i >= 0; i-- {
+		positions[i] = min(rowKanas[i].HorizontalPos, limit-m.LabelWidth(rowKanas[i].Kana))
+		limit = positions[i]
+	}
+	cursor := 0
+	for i, fk := range rowKanas {
+		positions[i] = max(positions[i], cursor)
+		cursor = positions[i] + m.LabelWidth(fk.Kana)
+	}
+	return positions
+}
+
+// renderQuiz draws the quiz question in a large box above its numbered
+// options, marking the right and wrong picks once answered
+func renderQuiz(m *model.Model) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	optionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("111")).Width(12)
+
+	borderColor := lipgloss.Color("63")
+	if m.QuizAnswer != -1 {
+		borderColor = lipgloss.Color("42")
+		if m.QuizOptions[m.QuizAnswer].Character != m.QuizQuestion.Kana.Character {
+			borderColor = lipgloss.Color("196")
+		}
+	}
+	question := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 4).
+		Render(KanaStyle.Render(m.QuizQuestion.Kana.Character))
+
+	var options []string
+	for i, o := range m.QuizOptions {
+		style := optionStyle
+		if m.QuizAnswer != -1 {
+			switch {
+			case o.Character == m.QuizQuestion.Kana.Character:
+				style = style.Foreground(lipgloss.Color("42")).Bold(true)
+			case i == m.QuizAnswer:
+				style = style.Foreground(lipgloss.Color("196")).Bold(true)
+			default:
+				style = style.Foreground(lipgloss.Color("241"))
+			}
+		}
+		options = append(options, dimStyle.Render(fmt.Sprintf("%d ", i+1))+style.Render(m.AnswerText(o)))
+	}
+	grid := lipgloss.JoinVertical(lipgloss.Left,
+		strings.Join(options[:min(2, len(options))], "  "),
+		"",
+		strings.Join(options[min(2, len(options)):], "  "),
+	)
+
+	content := lipgloss.JoinVertical(lipgloss.Center, question, "", "", grid)
+	return lipgloss.Place(m.PlayAreaWidth, m.MaxFallHeight, lipgloss.Center, lipgloss.Center, content)
+}
+
+// renderPauseOverlay replaces the play area while paused so the falling
+// kana cannot be studied
+func renderPauseOverlay(m *model.Model) string {
+	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var overlay strings.Builder
+	// Fits the smallest play field: title, blank line, options and help
+	overlay.WriteString(TitleStyle.UnsetMarginBottom().Render("⏸  PAUSED"))
+	overlay.WriteString("\n\n")
+	for o := model.PauseOption(0); o < model.PauseOptionCount; o++ {
+		if o == m.PauseCursor {
+			overlay.WriteString("▸ " + activeValueStyle.Render(o.String()))
+		} else {
+			overlay.WriteString("  " + dimStyle.Render(o.String()))
+		}
+		overlay.WriteString("\n")
+	}
+	overlay.WriteString(dimStyle.Render("Tab/ESC resume • ↑/↓ select • Enter confirm"))
+
+	return lipgloss.Place(m.PlayAreaWidth, m.MaxFallHeight, lipgloss.Center, lipgloss.Center, overlay.String())
+}
+
+func viewGameOver(m *model.Model) string {
+	var s strings.Builder
+
+	if m.Timed() {
+		s.WriteString(TitleStyle.Render("⏱  TIME'S UP ⏱"))
+	} else if m.Zen {
+		s.WriteString(TitleStyle.Render("🧘 SESSION OVER 🧘"))
+	} else {
+		s.WriteString(TitleStyle.Render("💀 GAME OVER 💀"))
+	}
+	s.WriteString("\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Width(14)
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("111"))
+	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	type result struct {
+		label string
+		value string
+	}
+	results := []result{
+		{"Score", fmt.Sprintf("%d points (%d correct)", m.GetPoints(), m.Correct)},
+		{"Accuracy", fmt.Sprintf("%.0f%%", m.Accuracy()*100)},
+	}
+	duration := m.Duration()
+	if m.Timed() {
+		results = append(results, result{"Answers/min", fmt.Sprintf("%.1f", m.AnswersPerMinute())})
+		duration = min(duration, m.TimeAttack.Duration())
+	}
+	results = append(results,
+		result{"Max Streak", fmt.Sprintf("%d", m.MaxStreak)},
+		result{"Level", fmt.Sprintf("%d", m.GetLevel())},
+		result{"Duration", duration.Round(time.Second).String()},
+	)
+	for _, r := range results {
+		s.WriteString(labelStyle.Render(r.label) + valueStyle.Render(r.value) + "\n")
+	}
+	s.WriteString("\n")
+
+	if m.ScoreRank == 1 {
+		s.WriteString(CorrectStyle.Render("🏆 New high score for " + m.ScoreConfig().String() + "!"))
+		s.WriteString("\n\n")
+	} else if m.ScoreRank > 1 && m.ScoreRank <= leaderboardSize {
+		s.WriteString(valueStyle.Render(fmt.Sprintf("🏅 #%d for %s", m.ScoreRank, m.ScoreConfig().String())))
+		s.WriteString("\n\n")
+	}
+
+	top := s.String()
+	var bottom strings.Builder
+	if m.Replaying {
+		// Playback has its own controls below the view
+		return top + viewMistakes(m, top+"\n\n") + bottom.String()
+	}
+
+	for o := model.GameOverOption(0); o < model.GameOverOptionCount; o++ {
+		if o == model.GameOverPractice && len(m.Mistakes) == 0 {
+			continue
+		}
+		if o == m.GameOverCursor {
+			bottom.WriteString("▸ " + activeValueStyle.Render(o.String()))
+		} else {
+			bottom.WriteString("  " + dimStyle.Render(o.String()))
+		}
+		bottom.WriteString("\n")
+	}
+	bottom.WriteString("\n")
+
+	if m.ReplayPath != "" {
+		// Paths easily outgrow the terminal, wrap them between directories
+		bottom.WriteString(dimStyle.Render(ansi.Wrap("🎞  Replay saved to "+m.ReplayPath, m.TermWidth, "/")))
+		bottom.WriteString("\n")
+	}
+	if m.ReplayError != "" {
+		bottom.WriteString(WrongStyle.Render("⚠ ") + dimStyle.Render(ansi.Wrap("replay: "+m.ReplayError, m.TermWidth-2, "/")))
+		bottom.WriteString("\n")
+	}
+
+	helpText := dimStyle.Render("↑/↓ select • Enter to confirm • ESC to quit")
+	bottom.WriteString(helpText)
+
+	return top + viewMistakes(m, top+bottom.String()) + bottom.String()
+}
+
+// viewMistakes lists the kana missed during the run, one per line when the
+// terminal has room for them around the rest of the results, else wrapped
+// several to a line and cut short
+func viewMistakes(m *model.Model, around string) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	var s strings.Builder
+
+	if len(m.Mistakes) == 0 {
+		s.WriteString(CorrectStyle.Render("No mistakes, well done!"))
+		s.WriteString("\n\n")
+		return s.String()
+	}
+
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255")).Render(fmt.Sprintf("Missed kana (%d):", len(m.Mistakes))))
+	s.WriteString("\n")
+	// Lines left once the header, the blank line after the list and the
+	// results around it are drawn
+	room := len(m.Mistakes)
+	if m.TermHeight > 0 {
+		room = m.TermHeight - lipgloss.Height(s.String()) - lipgloss.Height(around) - 1
+	}
+
+	if len(m.Mistakes) <= room {
+		for _, mistake := range m.Mistakes {
+			var counts []string
+			if mistake.Misses > 0 {
+				counts = append(counts, fmt.Sprintf("missed ×%d", mistake.Misses))
+			}
+			if mistake.Mistyped > 0 {
+				counts = append(counts, fmt.Sprintf("mistyped ×%d", mistake.Mistyped))
+			}
+			line := "  " + KanaStyle.Render(mistake.Kana.Character) + "  " +
+				CorrectStyle.Render(model.Romanize(mistake.Kana, m.Romanization)) + "  " +
+				dimStyle.Render(strings.Join(counts, ", "))
+			s.WriteString(line + "\n")
+		}
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	width := m.TermWidth
+	if width <= 0 {
+		width = 80
+	}
+	room = max(room, 1)
+	var lines []string
+	line := ""
+	for i, mistake := range m.Mistakes {
+		entry := KanaStyle.Render(mistake.Kana.Character) + " " +
+			CorrectStyle.Render(model.Romanize(mistake.Kana, m.Romanization)) + " " +
+			dimStyle.Render(fmt.Sprintf("×%d", mistake.Misses+mistake.Mistyped))
+		if line != "" && lipgloss.Width(line+"   "+entry) > width-2 {
+			lines = append(lines, line)
+			line = ""
+		}
+		if len(lines) == room {
+			// Keep the last line for what doesn't fit
+			last := lines[room-1]
+			more := dimStyle.Render(fmt.Sprintf("+%d more", len(m.Mistakes)-i))
+			for lipgloss.Width(last+"   "+more) > width-2 && strings.Contains(last, "   ") {
+				last = last[:strings.LastIndex(last, "   ")]
+			}
+			lines[room-1] = last + "   " + more
+			line = ""
+			break
+		}
+		if line != "" {
+			line += "   "
+		}
+		line += entry
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	for _, l := range lines {
+		s.WriteString("  " + l + "\n")
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// leaderboardSize is the number of scores shown per configuration
+const leaderboardSize = 10
+
+func viewLeaderboard(m *model.Model) string {
+	var s strings.Builder
+
+	s.WriteString(TitleStyle.Render("🏆 High Scores"))
+	s.WriteString("\n\n")
+
+	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("111"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if len(m.BoardConfigs) == 0 {
+		s.WriteString(dimStyle.Render("No scores yet"))
+		return s.String()
+	}
+	config := m.BoardConfigs[m.BoardCursor]
+
+	s.WriteString(activeValueStyle.Render(fmt.Sprintf("< %s >", config.String())))
+	s.WriteString("  " + dimStyle.Render(fmt.Sprintf("%d/%d", m.BoardCursor+1, len(m.BoardConfigs))))
+	s.WriteString("\n\n")
+
+	var top []scores.Score
+	if m.Scores != nil {
+		top = m.Scores.Top(config, leaderboardSize)
+	}
+	if len(top) == 0 {
+		s.WriteString(dimStyle.Render("No scores yet for this configuration"))
+		s.WriteString("\n\n")
+	} else {
+		row := "%-4s %8s %8s %6s  %s"
+		s.WriteString(headerStyle.Render(fmt.Sprintf(row, "#", "Points", "Correct", "Level", "Date")))
+		s.WriteString("\n")
+		for i, score := range top {
+			line := fmt.Sprintf(row,
+				fmt.Sprintf("%d.", i+1),
+				fmt.Sprintf("%d", score.Points),
+				fmt.Sprintf("%d", score.Correct),
+				fmt.Sprintf("%d", score.Level),
+				score.Date.Local().Format("2006-01-02 15:04"))
+			if i == 0 {
+				s.WriteString(activeValueStyle.Render(line))
+			} else {
+				s.WriteString(valueStyle.Render(line))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	if m.ScoresError != "" {
+		s.WriteString(WrongStyle.Render("⚠ ") + dimStyle.Render(m.ScoresError))
+		s.WriteString("\n\n")
+	}
+
+	helpText := dimStyle.Render("←/→ configuration • Enter/ESC back to menu")
+	s.WriteString(helpText)
+
+	return s.String()
+}
+
+// ViewReplayBar renders the playback controls of a replay: the speed, a
+// seek bar as wide as the terminal and the position in the run
+func ViewReplayBar(width int, pos, total time.Duration, speed int, paused bool) string {
+	activeValueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	state := "▶"
+	if paused {
+		state = "⏸"
+	}
+	label := fmt.Sprintf("%s %dx  ", state, speed)
+	position := fmt.Sprintf("  %s / %s", minutesSeconds(pos), minutesSeconds(total))
+
+	if width == 0 {
+		width = 60
+	}
+	barWidth := max(width-lipgloss.Width(label)-lipgloss.Width(position)-1, 10)
+	filled := 0
+	if total > 0 {
+		filled = int(int64(barWidth) * int64(pos) / int64(total))
+	}
+	bar := activeValueStyle.Render(strings.Repeat("━", filled)) + dimStyle.Render(strings.Repeat("─", barWidth-filled))
+
+	help := dimStyle.Render("Space play/pause • ←/→ seek 5s • 1/2/4 speed • q to quit")
+	return label + bar + position + "\n" + help
+}
+
+// minutesSeconds formats d as m:ss
+func minutesSeconds(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -1,38 +1,54 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 
+	"gokana/internal/engine"
 	"gokana/internal/game"
-	"gokana/internal/model"
 	"gokana/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type teaModel struct {
-	m *model.Model
+	e     *engine.Engine
+	start tea.Cmd
 }
 
 func (t teaModel) Init() tea.Cmd {
-	return game.Init()
+	return tea.Batch(game.Init(), t.start)
 }
 
 func (t teaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	updatedModel, cmd := game.Update(t.m, msg)
-	t.m = updatedModel
+	cmd := game.Update(t.e, msg)
 	return t, cmd
 }
 
 func (t teaModel) View() string {
-	return ui.View(t.m)
+	return ui.View(t.e.M)
+}
+
+// runGame runs the game in the terminal, start is the command of a run
+// already started, nil to open the menu
+func runGame(e *engine.Engine, start tea.Cmd) error {
+	_, err := tea.NewProgram(teaModel{e: e, start: start}).Run()
+	// Quitting mid-run leaves the last answers unsaved
+	game.SaveStores(e.M)
+	return err
 }
 
 func main() {
-	initialModel := game.InitialModel()
-	p := tea.NewProgram(teaModel{m: initialModel})
-	if _, err := p.Run(); err != nil {
+	err := run(os.Args[1:])
+	var usageErr usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(os.Stderr, "%v\n\n%s", err, usage)
+		os.Exit(2)
+	default:
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}